@@ -0,0 +1,10 @@
+#version 410 core
+
+uniform vec4 crosshairColor;
+
+out vec4 fragColor;
+
+void main()
+{
+    fragColor = crosshairColor;
+}
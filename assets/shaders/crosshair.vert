@@ -0,0 +1,10 @@
+#version 410 core
+
+layout(location = 0) in vec3 inPosition;
+
+uniform mat4 ortho;
+
+void main()
+{
+    gl_Position = ortho * vec4(inPosition, 1.0);
+}
@@ -0,0 +1,21 @@
+#version 410 core
+
+out vec4 outputColor;
+
+uniform sampler2D lightMap;
+
+#include "gbuffer.glsl"
+#include "fog.glsl"
+
+void main()
+{
+    ivec2 pixel = ivec2(gl_FragCoord.xy);
+    float depth = texelFetch(gDepth, pixel, 0).r;
+    if (depth >= 1.0) {
+        discard; // здесь уже нарисовано небо
+    }
+    vec3 pos = worldPosition(pixel, depth);
+    vec3 color = texelFetch(lightMap, pixel, 0).rgb;
+    outputColor = vec4(applyFog(color, distance(viewPos, pos)), 1.0);
+    gl_FragDepth = depth;
+}
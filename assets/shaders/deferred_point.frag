@@ -0,0 +1,39 @@
+#version 410 core
+
+flat in vec4 light;
+flat in vec3 color;
+
+out vec4 outputColor;
+
+#include "frame.glsl"
+#include "gbuffer.glsl"
+
+void main()
+{
+    ivec2 pixel = ivec2(gl_FragCoord.xy);
+    float depth = texelFetch(gDepth, pixel, 0).r;
+    vec4 material = texelFetch(gMaterial, pixel, 0);
+    if (depth >= 1.0 || material.b > 0.5) {
+        discard; // небо и сами светящиеся блоки
+    }
+    vec3 pos = worldPosition(pixel, depth);
+    vec3 toLight = light.xyz - pos;
+    float dist = length(toLight);
+    if (dist >= light.w) {
+        discard;
+    }
+
+    vec3 albedo = texelFetch(gAlbedo, pixel, 0).rgb;
+    vec3 N = normalize(texelFetch(gNormal, pixel, 0).xyz);
+    vec3 L = toLight / dist;
+    vec3 V = normalize(viewPos - pos);
+
+    // Плавное затухание до нуля на границе радиуса
+    float falloff = 1.0 - dist / light.w;
+    float attenuation = falloff * falloff;
+
+    vec3 diffuse = max(dot(N, L), 0.0) * albedo;
+    vec3 H = normalize(L + V);
+    float spec = material.r * 2.0 * pow(max(dot(N, H), 0.0), material.g * 256.0);
+    outputColor = vec4((diffuse + spec) * color * attenuation, 1.0);
+}
@@ -0,0 +1,27 @@
+#version 410 core
+
+layout(location = 0) in vec4 inLight; // позиция и радиус
+layout(location = 1) in vec3 inColor;
+
+#include "frame.glsl"
+
+flat out vec4 light;
+flat out vec3 color;
+
+// Вершины куба [-1, 1]: 12 треугольников, обход против часовой стрелки снаружи
+const vec3 cube[36] = vec3[](
+    vec3(-1,-1, 1), vec3( 1,-1, 1), vec3( 1, 1, 1), vec3( 1, 1, 1), vec3(-1, 1, 1), vec3(-1,-1, 1),
+    vec3( 1,-1,-1), vec3(-1,-1,-1), vec3(-1, 1,-1), vec3(-1, 1,-1), vec3( 1, 1,-1), vec3( 1,-1,-1),
+    vec3(-1,-1,-1), vec3(-1,-1, 1), vec3(-1, 1, 1), vec3(-1, 1, 1), vec3(-1, 1,-1), vec3(-1,-1,-1),
+    vec3( 1,-1, 1), vec3( 1,-1,-1), vec3( 1, 1,-1), vec3( 1, 1,-1), vec3( 1, 1, 1), vec3( 1,-1, 1),
+    vec3(-1, 1, 1), vec3( 1, 1, 1), vec3( 1, 1,-1), vec3( 1, 1,-1), vec3(-1, 1,-1), vec3(-1, 1, 1),
+    vec3(-1,-1,-1), vec3( 1,-1,-1), vec3( 1,-1, 1), vec3( 1,-1, 1), vec3(-1,-1, 1), vec3(-1,-1,-1)
+);
+
+void main()
+{
+    light = inLight;
+    color = inColor;
+    vec3 worldPos = inLight.xyz + cube[gl_VertexID] * inLight.w;
+    gl_Position = projection * view * vec4(worldPos, 1.0);
+}
@@ -0,0 +1,43 @@
+#version 410 core
+
+out vec4 outputColor;
+
+#include "frame.glsl"
+#include "material.glsl"
+#include "gbuffer.glsl"
+#include "shadow.glsl"
+#include "ao.glsl"
+
+void main()
+{
+    ivec2 pixel = ivec2(gl_FragCoord.xy);
+    float depth = texelFetch(gDepth, pixel, 0).r;
+    if (depth >= 1.0) {
+        discard; // небо
+    }
+    vec3 albedo = texelFetch(gAlbedo, pixel, 0).rgb;
+    vec4 material = texelFetch(gMaterial, pixel, 0);
+
+    // Светящиеся блоки освещение не меняет
+    if (material.b > 0.5) {
+        outputColor = vec4(albedo * emissiveStrength, 1.0);
+        return;
+    }
+
+    vec3 pos = worldPosition(pixel, depth);
+    vec3 N = normalize(texelFetch(gNormal, pixel, 0).xyz);
+    vec3 L = normalize(lightDir);
+    vec3 V = normalize(viewPos - pos);
+
+    vec3 ambient = ambientColor * albedo * ambientOcclusion((vec2(pixel) + 0.5) / vec2(textureSize(gDepth, 0)));
+    vec3 diffuse = max(dot(N, L), 0.0) * lightColor * albedo;
+
+    // Specular (Blinn-Phong)
+    vec3 H = normalize(L + V);
+    float spec = pow(max(dot(N, H), 0.0), material.g * 256.0);
+    vec3 specular = material.r * 2.0 * spec * lightColor;
+
+    float viewDepth = -(view * vec4(pos, 1.0)).z;
+    float shadow = calculateShadow(pos, viewDepth, N, L);
+    outputColor = vec4(ambient + (1.0 - shadow) * (diffuse + specular), 1.0);
+}
@@ -0,0 +1,14 @@
+#version 410 core
+flat in float fragMaterial;
+
+#include "material.glsl"
+
+void main()
+{
+    // Вода тень не отбрасывает
+    if (isWater(fragMaterial)) {
+        discard;
+    }
+    // Здесь выводим только глубину
+    // gl_FragDepth обновляется автоматически
+}
@@ -0,0 +1,20 @@
+#version 410 core
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec3 inNormal;
+layout(location = 2) in vec3 inColor;
+layout(location = 3) in vec3 inChunkOffset; // смещение чанка при multi-draw (иначе 0)
+layout(location = 4) in float inMaterial;
+
+#include "shadow_data.glsl"
+uniform int cascade; // слой карты теней, в который идёт отрисовка
+uniform mat4 model;
+
+flat out float fragMaterial;
+
+void main()
+{
+    vec4 worldPos = model * vec4(inPosition + inChunkOffset, 1.0);
+    gl_Position = lightSpaceMatrices[cascade] * worldPos;
+    fragMaterial = inMaterial;
+}
@@ -0,0 +1,9 @@
+#version 410 core
+
+// Полноэкранный треугольник из gl_VertexID, общий для проходов освещения, SSAO и постобработки
+
+void main()
+{
+    vec2 pos = vec2(float((gl_VertexID << 1) & 2), float(gl_VertexID & 2)) * 2.0 - 1.0;
+    gl_Position = vec4(pos, 0.0, 1.0);
+}
@@ -0,0 +1,33 @@
+#version 410 core
+
+in vec3 fragPos;
+in vec3 fragNormal;
+in vec3 fragColor;
+in vec3 fragTexCoord;
+flat in float fragMaterial;
+
+layout(location = 0) out vec4 outAlbedo;
+layout(location = 1) out vec4 outNormal;
+layout(location = 2) out vec4 outMaterial;
+
+#include "material.glsl"
+#include "weather.glsl"
+#include "block_texture.glsl"
+
+void main()
+{
+    if (isWater(fragMaterial)) {
+        discard;
+    }
+    vec3 N = normalize(fragNormal);
+
+    // Мокрая поверхность темнее и глаже: блик ярче и уже (как в прямом проходе)
+    float emissive = isEmissive(fragMaterial) ? 1.0 : 0.0;
+    float wet = emissive > 0.5 ? 0.0 : surfaceWetness(fragPos, N);
+    float shininess = mix(32.0, 128.0, wet);
+    float specularStrength = 0.5 + 1.5 * wet;
+
+    outAlbedo = vec4(fragColor * blockTexture(fragTexCoord) * (1.0 - 0.4 * wet), 1.0);
+    outNormal = vec4(N, 0.0);
+    outMaterial = vec4(specularStrength / 2.0, shininess / 256.0, emissive, 1.0);
+}
@@ -0,0 +1,31 @@
+#version 410 core
+
+// Вершины чанков для проходов, которым не нужны тени и вода:
+// G-буфер и предварительный проход нормалей SSAO
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec3 inNormal;
+layout(location = 2) in vec3 inColor;
+layout(location = 3) in vec3 inChunkOffset; // смещение чанка при multi-draw (иначе 0)
+layout(location = 4) in float inMaterial;
+layout(location = 5) in vec3 inTexCoord; // координаты в блоках и слой текстуры
+
+out vec3 fragPos;
+out vec3 fragNormal;
+out vec3 fragColor;
+out vec3 fragTexCoord;
+flat out float fragMaterial;
+
+#include "frame.glsl"
+uniform mat4 model;
+
+void main()
+{
+    vec4 worldPos = model * vec4(inPosition + inChunkOffset, 1.0);
+    fragPos = worldPos.xyz;
+    fragNormal = mat3(transpose(inverse(model))) * inNormal;
+    fragColor = inColor;
+    fragTexCoord = inTexCoord;
+    fragMaterial = inMaterial;
+    gl_Position = projection * view * worldPos;
+}
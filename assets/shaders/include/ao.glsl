@@ -0,0 +1,8 @@
+// Затенение ambient в шейдерах освещения (uv — координаты пикселя на экране, 0..1)
+uniform sampler2D aoMap;
+uniform bool aoEnabled;
+
+float ambientOcclusion(vec2 uv)
+{
+    return aoEnabled ? texture(aoMap, uv).r : 1.0;
+}
@@ -0,0 +1,10 @@
+// Цвет текстуры блока во фрагментных шейдерах: texCoord — координаты в блоках и слой,
+// грани без текстуры (вода, модель игрока) берут цвет только из вершин
+uniform sampler2DArray blockTextures;
+
+vec3 blockTexture(vec3 texCoord)
+{
+    // Выборка вне ветвления: производные для mip-уровня нужны всем соседним пикселям
+    vec3 color = texture(blockTextures, texCoord).rgb;
+    return texCoord.z < 0.0 ? vec3(1.0) : color;
+}
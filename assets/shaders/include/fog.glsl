@@ -0,0 +1,16 @@
+// Туман по расстоянию до камеры; под водой — плотный цветной туман и цветокоррекция.
+// Параметры тумана и признак «под водой» — в блоке FrameData
+#include "frame.glsl"
+
+vec3 applyFog(vec3 color, float dist)
+{
+    if (underwater) {
+        // Цветокоррекция: вода поглощает красный канал сильнее синего
+        float luma = dot(color, vec3(0.299, 0.587, 0.114));
+        vec3 graded = mix(vec3(luma), color, 0.6) * vec3(0.55, 0.8, 1.0);
+        float uwFactor = clamp((underwaterFogEnd - dist) / underwaterFogEnd, 0.0, 1.0);
+        return mix(underwaterColor, graded, uwFactor * uwFactor);
+    }
+    float fogFactor = clamp((fogEnd - dist) / (fogEnd - fogStart), 0.0, 1.0);
+    return mix(fogColor, color, fogFactor);
+}
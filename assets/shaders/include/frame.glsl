@@ -0,0 +1,15 @@
+// Блок FrameData: камера, свет и туман прохода
+layout(std140) uniform FrameData {
+    mat4 view;
+    mat4 projection;
+    vec3 viewPos;
+    float fogStart;
+    vec3 lightDir;          // направление на солнце днём или на луну ночью
+    float fogEnd;
+    vec3 lightColor;
+    float underwaterFogEnd;
+    vec3 ambientColor;
+    bool underwater;        // глаз камеры внутри жидкости
+    vec3 fogColor;
+    vec3 underwaterColor;
+};
@@ -0,0 +1,14 @@
+// Чтение G-буфера по пикселю экрана и восстановление мировой позиции по глубине
+uniform sampler2D gAlbedo;
+uniform sampler2D gNormal;
+uniform sampler2D gMaterial;
+uniform sampler2D gDepth;
+uniform mat4 invViewProjection;
+
+vec3 worldPosition(ivec2 pixel, float depth)
+{
+    vec2 uv = (vec2(pixel) + 0.5) / vec2(textureSize(gDepth, 0));
+    vec4 clip = vec4(uv * 2.0 - 1.0, depth * 2.0 - 1.0, 1.0);
+    vec4 world = invViewProjection * clip;
+    return world.xyz / world.w;
+}
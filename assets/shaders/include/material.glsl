@@ -0,0 +1,6 @@
+// Материалы вершин (world.MaterialOpaque, MaterialWater, MaterialEmissive)
+bool isWater(float material)    { return material > 0.5 && material < 1.5; }
+bool isEmissive(float material) { return material > 1.5; }
+
+// Свечение светящихся блоков (лава, факелы): их цвет, умноженный на яркость
+const float emissiveStrength = 1.6;
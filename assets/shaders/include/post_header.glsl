@@ -0,0 +1,14 @@
+#version 410 core
+
+// Общее начало шейдеров постобработки
+
+out vec4 outputColor;
+
+uniform sampler2D source; // результат предыдущего прохода
+uniform vec2 texelSize;   // размер текселя source
+uniform vec2 targetSize;  // размер цели в пикселях
+
+vec2 screenUV()
+{
+    return gl_FragCoord.xy / targetSize;
+}
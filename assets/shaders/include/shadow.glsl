@@ -0,0 +1,61 @@
+// Каскадные тени: выборка каскада с PCF 5x5 и плавный переход между каскадами
+// === ТЕНИ (каскады) ===
+#include "shadow_data.glsl"
+uniform sampler2DArray shadowMap;
+
+// Тень из одного каскада с PCF 5x5
+float cascadeShadow(int cascade, vec3 worldPos, vec3 normal, vec3 lightDir)
+{
+    vec4 lightSpacePos = lightSpaceMatrices[cascade] * vec4(worldPos, 1.0);
+    vec3 projCoords = lightSpacePos.xyz / lightSpacePos.w;
+    projCoords = projCoords * 0.5 + 0.5; // Приводим координаты в диапазон [0, 1]
+
+    // Проверка выхода за пределы карты каскада
+    if (projCoords.x < 0.0 || projCoords.x > 1.0 ||
+        projCoords.y < 0.0 || projCoords.y > 1.0 ||
+        projCoords.z > 1.0)
+    {
+        return 0.0;
+    }
+
+    float currentDepth = projCoords.z;
+
+    // Динамический bias: дальние каскады крупнее, им нужен больший сдвиг
+    float bias = max(0.0005 * (1.0 - dot(normal, lightDir)), 0.0001) * float(cascade + 1);
+
+    float shadow = 0.0;
+    vec2 texelSize = 1.0 / vec2(textureSize(shadowMap, 0).xy);
+    for (int x = -2; x <= 2; ++x) {
+        for (int y = -2; y <= 2; ++y) {
+            float pcfDepth = texture(shadowMap, vec3(projCoords.xy + vec2(x, y) * texelSize, float(cascade))).r;
+            shadow += currentDepth - bias > pcfDepth ? 1.0 : 0.0;
+        }
+    }
+    return shadow / 25.0;
+}
+
+// Выбор каскада по глубине вида и плавный переход к следующему у его дальней границы
+float calculateShadow(vec3 worldPos, float viewDepth, vec3 normal, vec3 lightDir)
+{
+    int cascade = cascadeCount;
+    for (int i = 0; i < cascadeCount; ++i) {
+        if (viewDepth < cascadeSplits[i]) {
+            cascade = i;
+            break;
+        }
+    }
+    if (cascade >= cascadeCount) {
+        return 0.0; // дальше последнего каскада теней нет
+    }
+
+    float shadow = cascadeShadow(cascade, worldPos, normal, lightDir);
+
+    float splitNear = cascade == 0 ? 0.0 : cascadeSplits[cascade - 1];
+    float blendStart = cascadeSplits[cascade] - (cascadeSplits[cascade] - splitNear) * cascadeBlend;
+    if (viewDepth > blendStart) {
+        float t = (viewDepth - blendStart) / (cascadeSplits[cascade] - blendStart);
+        float next = cascade + 1 < cascadeCount ? cascadeShadow(cascade + 1, worldPos, normal, lightDir) : 0.0;
+        shadow = mix(shadow, next, t);
+    }
+    return shadow;
+}
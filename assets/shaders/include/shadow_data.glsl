@@ -0,0 +1,8 @@
+// Блок ShadowData: матрицы и дальние границы каскадов теней
+#define MAX_CASCADES 4
+layout(std140) uniform ShadowData {
+    mat4 lightSpaceMatrices[MAX_CASCADES];
+    vec4 cascadeSplits;     // дальняя граница каждого каскада по глубине вида
+    int cascadeCount;
+    float cascadeBlend;     // доля каскада, на которой он смешивается со следующим
+};
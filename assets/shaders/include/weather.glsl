@@ -0,0 +1,23 @@
+// Намокание поверхностей под дождём по карте высот столбцов
+// === ПОГОДА ===
+uniform float wetness;          // 0..1, насколько намок мир под открытым небом
+uniform sampler2D heightMap;    // высоты столбцов вокруг камеры (y+1 верхнего блока)
+uniform ivec2 heightMapOrigin;
+uniform int heightMapSize;
+
+// Намокание грани: только если воздух перед ней открыт небу.
+// Вне карты высот считаем поверхность открытой
+float surfaceWetness(vec3 worldPos, vec3 N)
+{
+    if (wetness <= 0.0) {
+        return 0.0;
+    }
+    vec3 probe = worldPos + N * 0.5; // точка в воздухе перед гранью
+    ivec2 column = ivec2(floor(probe.xz)) - heightMapOrigin;
+    if (all(greaterThanEqual(column, ivec2(0))) && all(lessThan(column, ivec2(heightMapSize)))) {
+        if (probe.y < texelFetch(heightMap, column, 0).r) {
+            return 0.0;
+        }
+    }
+    return wetness * (N.y > 0.5 ? 1.0 : 0.6);
+}
@@ -0,0 +1,11 @@
+#include "post_header.glsl"
+
+uniform sampler2D bloom;
+uniform float intensity;
+
+void main()
+{
+    vec2 uv = screenUV();
+    vec3 color = texture(source, uv).rgb + texture(bloom, uv).rgb * intensity;
+    outputColor = vec4(color, 1.0);
+}
@@ -0,0 +1,29 @@
+#include "post_header.glsl"
+
+// Уменьшение вдвое фильтром 4×4 (четыре билинейные выборки); на первом уровне —
+// мягкий порог яркости, чтобы свечение не появлялось скачком
+uniform float threshold;
+uniform bool prefilter;
+
+vec3 applyThreshold(vec3 c)
+{
+    float brightness = max(c.r, max(c.g, c.b));
+    float knee = threshold * 0.5;
+    float soft = clamp(brightness - threshold + knee, 0.0, 2.0 * knee);
+    soft = soft * soft / (4.0 * knee + 1e-4);
+    return c * max(soft, brightness - threshold) / max(brightness, 1e-4);
+}
+
+void main()
+{
+    vec2 uv = screenUV();
+    vec3 c = texture(source, uv + texelSize * vec2(-1.0, -1.0)).rgb
+           + texture(source, uv + texelSize * vec2( 1.0, -1.0)).rgb
+           + texture(source, uv + texelSize * vec2(-1.0,  1.0)).rgb
+           + texture(source, uv + texelSize * vec2( 1.0,  1.0)).rgb;
+    c *= 0.25;
+    if (prefilter) {
+        c = applyThreshold(c);
+    }
+    outputColor = vec4(c, 1.0);
+}
@@ -0,0 +1,17 @@
+#include "post_header.glsl"
+
+// Увеличение с размытием шатром 3×3; результат прибавляется к более крупному уровню
+void main()
+{
+    vec2 uv = screenUV();
+    vec3 c = texture(source, uv).rgb * 4.0;
+    c += (texture(source, uv + texelSize * vec2(-1.0, 0.0)).rgb
+        + texture(source, uv + texelSize * vec2( 1.0, 0.0)).rgb
+        + texture(source, uv + texelSize * vec2(0.0, -1.0)).rgb
+        + texture(source, uv + texelSize * vec2(0.0,  1.0)).rgb) * 2.0;
+    c += texture(source, uv + texelSize * vec2(-1.0, -1.0)).rgb
+       + texture(source, uv + texelSize * vec2( 1.0, -1.0)).rgb
+       + texture(source, uv + texelSize * vec2(-1.0,  1.0)).rgb
+       + texture(source, uv + texelSize * vec2( 1.0,  1.0)).rgb;
+    outputColor = vec4(c / 16.0, 1.0);
+}
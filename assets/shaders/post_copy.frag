@@ -0,0 +1,6 @@
+#include "post_header.glsl"
+
+void main()
+{
+    outputColor = vec4(texture(source, screenUV()).rgb, 1.0);
+}
@@ -0,0 +1,33 @@
+#include "post_header.glsl"
+
+// FXAA: направление края по яркости соседей и выборки вдоль него
+const float FXAA_SPAN_MAX = 8.0;
+const float FXAA_REDUCE_MUL = 1.0 / 8.0;
+const float FXAA_REDUCE_MIN = 1.0 / 128.0;
+
+void main()
+{
+    vec2 uv = screenUV();
+    const vec3 lumaWeights = vec3(0.299, 0.587, 0.114);
+    float lumaNW = dot(texture(source, uv + vec2(-1.0, -1.0) * texelSize).rgb, lumaWeights);
+    float lumaNE = dot(texture(source, uv + vec2( 1.0, -1.0) * texelSize).rgb, lumaWeights);
+    float lumaSW = dot(texture(source, uv + vec2(-1.0,  1.0) * texelSize).rgb, lumaWeights);
+    float lumaSE = dot(texture(source, uv + vec2( 1.0,  1.0) * texelSize).rgb, lumaWeights);
+    vec3 rgbM = texture(source, uv).rgb;
+    float lumaM = dot(rgbM, lumaWeights);
+
+    float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+    float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+
+    vec2 dir = vec2(-((lumaNW + lumaNE) - (lumaSW + lumaSE)), (lumaNW + lumaSW) - (lumaNE + lumaSE));
+    float dirReduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * 0.25 * FXAA_REDUCE_MUL, FXAA_REDUCE_MIN);
+    float rcpDirMin = 1.0 / (min(abs(dir.x), abs(dir.y)) + dirReduce);
+    dir = clamp(dir * rcpDirMin, vec2(-FXAA_SPAN_MAX), vec2(FXAA_SPAN_MAX)) * texelSize;
+
+    vec3 rgbA = 0.5 * (texture(source, uv + dir * (1.0 / 3.0 - 0.5)).rgb
+                     + texture(source, uv + dir * (2.0 / 3.0 - 0.5)).rgb);
+    vec3 rgbB = rgbA * 0.5 + 0.25 * (texture(source, uv - dir * 0.5).rgb
+                                   + texture(source, uv + dir * 0.5).rgb);
+    float lumaB = dot(rgbB, lumaWeights);
+    outputColor = vec4((lumaB < lumaMin || lumaB > lumaMax) ? rgbA : rgbB, 1.0);
+}
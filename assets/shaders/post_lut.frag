@@ -0,0 +1,12 @@
+#include "post_header.glsl"
+
+// Цветокоррекция по 3D-таблице; выборка по центрам крайних ячеек
+uniform sampler3D lut;
+uniform float lutSize;
+
+void main()
+{
+    vec3 color = clamp(texture(source, screenUV()).rgb, 0.0, 1.0);
+    vec3 coord = color * (lutSize - 1.0) / lutSize + 0.5 / lutSize;
+    outputColor = vec4(texture(lut, coord).rgb, 1.0);
+}
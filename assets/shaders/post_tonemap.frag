@@ -0,0 +1,15 @@
+#include "post_header.glsl"
+
+// Экспозиция и тональная кривая ACES (аппроксимация Нарковича)
+uniform float exposure;
+
+vec3 aces(vec3 x)
+{
+    return clamp((x * (2.51 * x + 0.03)) / (x * (2.43 * x + 0.59) + 0.14), 0.0, 1.0);
+}
+
+void main()
+{
+    vec3 color = texture(source, screenUV()).rgb * exposure;
+    outputColor = vec4(aces(color), 1.0);
+}
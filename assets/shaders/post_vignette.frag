@@ -0,0 +1,12 @@
+#include "post_header.glsl"
+
+uniform float strength;
+
+void main()
+{
+    vec2 uv = screenUV();
+    vec2 centered = (uv - 0.5) * vec2(targetSize.x / targetSize.y, 1.0);
+    float vignette = smoothstep(1.0, 0.35, length(centered));
+    vec3 color = texture(source, uv).rgb * mix(1.0, vignette, strength);
+    outputColor = vec4(color, 1.0);
+}
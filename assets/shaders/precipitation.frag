@@ -0,0 +1,24 @@
+#version 410 core
+
+in vec2 uv;
+in float alpha;
+
+uniform vec3 particleColor;
+uniform int snow;
+
+out vec4 outputColor;
+
+void main()
+{
+    float shape;
+    if (snow == 1) {
+        shape = smoothstep(0.5, 0.2, length(uv - 0.5));
+    } else {
+        shape = (1.0 - abs(uv.x * 2.0 - 1.0)) * 0.45;
+    }
+    float a = alpha * shape;
+    if (a <= 0.01) {
+        discard;
+    }
+    outputColor = vec4(particleColor, a);
+}
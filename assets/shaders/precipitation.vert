@@ -0,0 +1,78 @@
+#version 410 core
+
+uniform mat4 view;
+uniform mat4 projection;
+uniform vec3 cameraPos;
+uniform float time;
+uniform float radius;    // полуширина области частиц по XZ
+uniform float boxHeight; // высота области частиц
+uniform vec2 wind;
+uniform int snow;
+
+// Карта высот столбцов: частица ниже верха своего столбца не видна
+uniform sampler2D heightMap;
+uniform ivec2 heightMapOrigin;
+uniform int heightMapSize;
+
+out vec2 uv;
+out float alpha;
+
+float hash(float n)
+{
+    return fract(sin(n) * 43758.5453);
+}
+
+// Углы квадрата из двух треугольников
+const vec2 corners[6] = vec2[](
+    vec2(0.0, 0.0), vec2(1.0, 0.0), vec2(1.0, 1.0),
+    vec2(1.0, 1.0), vec2(0.0, 1.0), vec2(0.0, 0.0)
+);
+
+void main()
+{
+    float id = float(gl_InstanceID);
+    vec3 seed = vec3(hash(id * 1.17 + 0.5), hash(id * 2.31 + 7.0), hash(id * 3.73 + 13.0));
+
+    float speed = snow == 1 ? 1.5 + seed.y : 14.0 + 4.0 * seed.x;
+    float fall = time * speed;
+
+    // Частицы закреплены в мире и повторяются плиткой вокруг камеры,
+    // поэтому не «едут» вместе с ней
+    float size = 2.0 * radius;
+    vec3 p;
+    p.xz = seed.xz * size + wind * fall;
+    if (snow == 1) {
+        p.xz += 0.6 * vec2(sin(time * 1.3 + seed.x * 6.28), cos(time * 1.1 + seed.z * 6.28));
+    }
+    p.xz = cameraPos.xz + mod(p.xz - cameraPos.xz + radius, size) - radius;
+    p.y = cameraPos.y + mod(seed.y * boxHeight - fall - cameraPos.y + boxHeight * 0.5, boxHeight) - boxHeight * 0.5;
+
+    ivec2 column = ivec2(floor(p.xz)) - heightMapOrigin;
+    if (all(greaterThanEqual(column, ivec2(0))) && all(lessThan(column, ivec2(heightMapSize)))) {
+        if (p.y < texelFetch(heightMap, column, 0).r) {
+            gl_Position = vec4(0.0, 0.0, 2.0, 1.0); // за дальней плоскостью — отсечётся
+            alpha = 0.0;
+            return;
+        }
+    }
+
+    vec2 corner = corners[gl_VertexID];
+    uv = corner;
+    vec3 toCamera = normalize(cameraPos - p);
+    vec3 worldPos;
+    if (snow == 1) {
+        // Снежинка — квадрат, повёрнутый к камере
+        vec3 right = vec3(view[0][0], view[1][0], view[2][0]);
+        vec3 up = vec3(view[0][1], view[1][1], view[2][1]);
+        worldPos = p + (right * (corner.x - 0.5) + up * (corner.y - 0.5)) * 0.08;
+    } else {
+        // Капля — узкая полоса вдоль скорости (размытие движением)
+        vec3 axis = normalize(vec3(wind.x, -1.0, wind.y));
+        vec3 side = normalize(cross(axis, toCamera));
+        worldPos = p + side * (corner.x - 0.5) * 0.02 + axis * corner.y * speed * 0.04;
+    }
+
+    float dist = length(cameraPos.xz - p.xz);
+    alpha = 1.0 - smoothstep(radius * 0.7, radius, dist);
+    gl_Position = projection * view * vec4(worldPos, 1.0);
+}
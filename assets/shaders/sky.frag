@@ -0,0 +1,176 @@
+#version 410 core
+
+in vec2 ndc;
+out vec4 outputColor;
+
+uniform mat4 invViewProj;
+uniform vec3 sunDir;
+uniform vec3 moonDir;
+uniform float nightFactor;
+uniform float time;
+
+// Атмосфера: коэффициенты уже умножены на толщину слоя
+uniform vec3 betaRayleigh;
+uniform float betaMie;
+uniform float mieG;
+uniform float sunIntensity;
+uniform float moonIntensity;
+uniform float exposure;
+uniform vec3 nightColor;
+uniform float discCos; // косинус углового радиуса дисков
+
+// Облака: два слоя шума на заданных высотах, покрытие из погоды
+uniform vec3 cameraPos;
+uniform vec3 cloudLight;
+uniform vec2 cloudLayers;
+uniform float cloudScale;
+uniform vec2 cloudWind;
+uniform float cloudCover;
+uniform float overcast;
+uniform float flash;
+uniform vec3 lightningColor;
+uniform bool hdrOutput; // вывод в HDR-буфер: яркость диска солнца не ограничена
+uniform float sunGlow;
+
+const float PI = 3.14159265;
+
+// Оптическая масса атмосферы (Кастен–Янг), ниже горизонта угол ограничен
+float airMass(float cosZenith)
+{
+    float zenith = min(degrees(acos(clamp(cosZenith, -1.0, 1.0))), 93.0);
+    return 1.0 / (cos(radians(zenith)) + 0.50572 * pow(96.07995 - zenith, -1.6364));
+}
+
+vec3 transmittance(float cosZenith)
+{
+    return exp(-(betaRayleigh + betaMie) * airMass(cosZenith));
+}
+
+// Однократное рассеяние света источника вдоль луча dir (Хоффман–Притхэм)
+vec3 inscatter(vec3 dir, vec3 L, float intensity)
+{
+    float mu = dot(dir, L);
+    float phaseR = 3.0 / (16.0 * PI) * (1.0 + mu * mu);
+    float phaseM = (1.0 - mieG * mieG) / (4.0 * PI * pow(1.0 + mieG * mieG - 2.0 * mieG * mu, 1.5));
+    vec3 extinction = exp(-(betaRayleigh + betaMie) * airMass(max(dir.y, 0.0)));
+    vec3 scatter = (betaRayleigh * phaseR + betaMie * phaseM) / (betaRayleigh + betaMie);
+    return intensity * scatter * (1.0 - extinction) * transmittance(L.y);
+}
+
+float hash(vec3 p)
+{
+    p = fract(p * 0.3183099 + 0.1);
+    p *= 17.0;
+    return fract(p.x * p.y * p.z * (p.x + p.y + p.z));
+}
+
+// Звёзды: по одной случайной точке в части ячеек сетки на сфере радиуса 300
+float stars(vec3 dir)
+{
+    vec3 p = dir * 300.0;
+    vec3 cell = floor(p);
+    float h = hash(cell);
+    if (h < 0.997) {
+        return 0.0;
+    }
+    vec3 center = cell + 0.5 + (vec3(hash(cell + 1.7), hash(cell + 3.1), hash(cell + 5.3)) - 0.5) * 0.6;
+    float d = length(p - center);
+    float twinkle = 0.7 + 0.3 * sin(time * (2.0 + h * 40.0) + h * 100.0);
+    return smoothstep(0.3, 0.0, d) * twinkle * (h - 0.997) / 0.003;
+}
+
+float noise2(vec2 p)
+{
+    vec2 i = floor(p);
+    vec2 f = fract(p);
+    vec2 u = f * f * (3.0 - 2.0 * f);
+    float a = hash(vec3(i, 0.0));
+    float b = hash(vec3(i + vec2(1.0, 0.0), 0.0));
+    float c = hash(vec3(i + vec2(0.0, 1.0), 0.0));
+    float d = hash(vec3(i + vec2(1.0, 1.0), 0.0));
+    return mix(mix(a, b, u.x), mix(c, d, u.x), u.y);
+}
+
+float fbm(vec2 p)
+{
+    float sum = 0.0;
+    float amp = 0.5;
+    for (int i = 0; i < 5; ++i) {
+        sum += amp * noise2(p);
+        p = p * 2.03 + vec2(17.1, 9.2);
+        amp *= 0.5;
+    }
+    return sum;
+}
+
+// Плотность слоя облаков на высоте height вдоль луча dir; к горизонту слой растворяется
+float cloudLayer(vec3 dir, float height, float offset)
+{
+    if (dir.y <= 0.01) {
+        return 0.0;
+    }
+    float t = (height - cameraPos.y) / dir.y;
+    if (t <= 0.0) {
+        return 0.0;
+    }
+    vec2 p = (cameraPos.xz + dir.xz * t + cloudWind * time) * cloudScale + offset;
+    float n = fbm(p);
+    float density = smoothstep(1.0 - cloudCover, 1.0 - cloudCover + 0.35, n);
+    return density * exp(-t * 0.00015);
+}
+
+void main()
+{
+    vec4 far = invViewProj * vec4(ndc, 1.0, 1.0);
+    vec3 dir = normalize(far.xyz / far.w);
+
+    float sunFade = smoothstep(-0.1, 0.02, sunDir.y);
+    float moonFade = smoothstep(-0.1, 0.02, moonDir.y);
+    vec3 color = nightColor
+        + inscatter(dir, sunDir, sunIntensity * sunFade)
+        + inscatter(dir, moonDir, moonIntensity * moonFade);
+
+    // Прозрачность атмосферы вдоль луча: у горизонта диски и звёзды тускнеют
+    vec3 viewTrans = transmittance(max(dir.y, 0.0));
+    float aboveHorizon = smoothstep(-0.02, 0.02, dir.y);
+
+    // Диск солнца с потемнением к краю, окрашен пропусканием атмосферы
+    vec3 sunDisc = vec3(0.0);
+    float sunCos = dot(dir, sunDir);
+    if (sunCos > discCos) {
+        float r = sqrt(clamp((1.0 - sunCos) / (1.0 - discCos), 0.0, 1.0));
+        float limb = 1.0 - 0.6 * r * r;
+        sunDisc = viewTrans * sunIntensity * limb * smoothstep(1.0, 0.9, r) * aboveHorizon;
+    }
+    color += sunDisc;
+
+    // Диск луны; днём почти не виден на ярком небе
+    float moonCos = dot(dir, moonDir);
+    if (moonCos > discCos) {
+        float r = sqrt(clamp((1.0 - moonCos) / (1.0 - discCos), 0.0, 1.0));
+        float spots = 0.85 + 0.15 * hash(floor(dir * 900.0));
+        color += viewTrans * vec3(0.9, 0.92, 1.0) * spots * smoothstep(1.0, 0.9, r) * aboveHorizon;
+    }
+
+    color += vec3(stars(dir)) * nightFactor * viewTrans * aboveHorizon;
+
+    vec3 result = 1.0 - exp(-exposure * color);
+
+    // Кривая выше насыщает диск до белого; в HDR-буфер добавляем его яркость сверх неё
+    if (hdrOutput) {
+        result += sunDisc * sunGlow;
+    }
+
+    // Облака поверх неба: нижний слой плотнее и темнее снизу
+    float low = cloudLayer(dir, cloudLayers.x, 0.0);
+    float high = cloudLayer(dir, cloudLayers.y, 31.7) * 0.6;
+    result = mix(result, cloudLight * 0.9, high);
+    result = mix(result, cloudLight * mix(1.0, 0.7, low), low);
+
+    // Сплошная облачность — серое небо той же яркости (как overcastColor на CPU)
+    float luma = dot(result, vec3(0.299, 0.587, 0.114));
+    result = mix(result, vec3(luma) * 0.75, overcast);
+    result += lightningColor * flash * 0.6;
+
+    outputColor = vec4(result, 1.0);
+}
@@ -0,0 +1,11 @@
+#version 410 core
+
+out vec2 ndc;
+
+// Полноэкранный треугольник из gl_VertexID
+void main()
+{
+    vec2 pos = vec2(float((gl_VertexID << 1) & 2), float(gl_VertexID & 2)) * 2.0 - 1.0;
+    ndc = pos;
+    gl_Position = vec4(pos, 1.0, 1.0);
+}
@@ -0,0 +1,50 @@
+#include "post_header.glsl"
+
+// SSAO по полусфере вокруг нормали: source — глубина, выборки ядра поворачиваются
+// текстурой шума и проверяются по глубине кадра; вклад дальних перепадов глубины гасится
+uniform sampler2D normalMap; // мировые нормали
+uniform sampler2D noiseMap;
+uniform vec2 noiseScale;
+uniform mat4 view;
+uniform mat4 projection;
+uniform mat4 invProjection;
+uniform vec3 kernel[64];
+uniform int kernelSize;
+uniform float radius;
+uniform float intensity;
+
+vec3 viewPosition(vec2 uv)
+{
+    float depth = texture(source, uv).r;
+    vec4 p = invProjection * vec4(uv * 2.0 - 1.0, depth * 2.0 - 1.0, 1.0);
+    return p.xyz / p.w;
+}
+
+void main()
+{
+    vec2 uv = screenUV();
+    if (texture(source, uv).r >= 1.0) {
+        outputColor = vec4(1.0);
+        return;
+    }
+    vec3 P = viewPosition(uv);
+    vec3 N = normalize(mat3(view) * texture(normalMap, uv).xyz);
+
+    // Базис вокруг нормали со случайным поворотом из текстуры шума
+    vec3 randomVec = texture(noiseMap, uv * noiseScale).xyz;
+    vec3 T = normalize(randomVec - N * dot(randomVec, N));
+    vec3 B = cross(N, T);
+    mat3 TBN = mat3(T, B, N);
+
+    float occlusion = 0.0;
+    for (int i = 0; i < kernelSize; ++i) {
+        vec3 S = P + TBN * kernel[i] * radius;
+        vec4 offset = projection * vec4(S, 1.0);
+        offset.xy = offset.xy / offset.w * 0.5 + 0.5;
+        float sceneZ = viewPosition(offset.xy).z;
+        float rangeCheck = smoothstep(0.0, 1.0, radius / abs(P.z - sceneZ));
+        occlusion += (sceneZ >= S.z + 0.025 ? 1.0 : 0.0) * rangeCheck;
+    }
+    float ao = 1.0 - occlusion / float(kernelSize);
+    outputColor = vec4(vec3(pow(ao, intensity)), 1.0);
+}
@@ -0,0 +1,29 @@
+#include "post_header.glsl"
+
+// Двусторонний гауссов фильтр вдоль direction: вес соседа падает с разницей линейной глубины
+uniform sampler2D depthMap;
+uniform vec2 direction;
+uniform float near;
+uniform float far;
+
+float linearDepth(vec2 uv)
+{
+    float z = texture(depthMap, uv).r * 2.0 - 1.0;
+    return 2.0 * near * far / (far + near - z * (far - near));
+}
+
+void main()
+{
+    vec2 uv = screenUV();
+    float centerDepth = linearDepth(uv);
+    float sum = 0.0;
+    float weightSum = 0.0;
+    for (int i = -4; i <= 4; ++i) { // 4 текселя в каждую сторону
+        vec2 sampleUV = uv + direction * texelSize * float(i);
+        float depthDiff = abs(linearDepth(sampleUV) - centerDepth) / (centerDepth * 0.02 + 0.05);
+        float weight = exp(-float(i * i) / 8.0) * exp(-depthDiff * depthDiff);
+        sum += texture(source, sampleUV).r * weight;
+        weightSum += weight;
+    }
+    outputColor = vec4(vec3(sum / weightSum), 1.0);
+}
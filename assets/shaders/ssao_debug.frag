@@ -0,0 +1,6 @@
+#include "post_header.glsl"
+
+void main()
+{
+    outputColor = vec4(vec3(texture(source, screenUV()).r), 1.0);
+}
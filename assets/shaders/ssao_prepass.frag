@@ -0,0 +1,18 @@
+#version 410 core
+
+// Предварительный проход прямого конвейера: только мировые нормали непрозрачного
+
+in vec3 fragNormal;
+flat in float fragMaterial;
+
+out vec4 outNormal;
+
+#include "material.glsl"
+
+void main()
+{
+    if (isWater(fragMaterial)) {
+        discard;
+    }
+    outNormal = vec4(normalize(fragNormal), 0.0);
+}
@@ -0,0 +1,17 @@
+#version 410 core
+
+in vec2 fragTexCoord; // Координаты в атласе из вершинного шейдера
+in vec4 fragTextColor;
+uniform sampler2D textTexture; // Атлас глифов: покрытие или поле расстояний в канале R
+uniform bool sdf; // Атлас — поле расстояний (0.5 — контур глифа)
+
+out vec4 fragColor; // Итоговый цвет фрагмента
+
+void main()
+{
+    float value = texture(textTexture, fragTexCoord / vec2(textureSize(textTexture, 0))).r;
+    // Ширина перехода — около пикселя экрана при любом масштабе текста
+    float edge = max(fwidth(value), 1e-4) * 0.7;
+    float alpha = sdf ? smoothstep(0.5 - edge, 0.5 + edge, value) : value;
+    fragColor = vec4(fragTextColor.rgb, fragTextColor.a * alpha);
+}
@@ -0,0 +1,17 @@
+#version 410 core
+
+layout(location = 0) in vec2 inPosition; // Позиция вершины на экране
+layout(location = 1) in vec2 inTexCoord; // Координаты глифа в атласе, в пикселях
+layout(location = 2) in vec4 inColor;    // Цвет текста (RGBA)
+
+uniform mat4 ortho; // Ортографическая матрица
+
+out vec2 fragTexCoord; // Передача координат текстуры в фрагментный шейдер
+out vec4 fragTextColor;
+
+void main()
+{
+    fragTexCoord = inTexCoord;
+    fragTextColor = inColor;
+    gl_Position = ortho * vec4(inPosition, 0.0, 1.0);
+}
@@ -0,0 +1,131 @@
+#version 410 core
+
+in vec3 fragPos;
+in vec3 fragNormal;
+in vec3 fragColor;
+in vec3 fragTexCoord;
+in float fragDist;
+in float fragViewDepth;
+flat in float fragMaterial;
+
+out vec4 outputColor;
+
+// === ПАРАМЕТРЫ ОСВЕЩЕНИЯ (свет и камера — в блоке FrameData) ===
+#include "frame.glsl"
+uniform float shininess;     
+uniform float specularStrength; 
+
+#include "material.glsl"
+#include "fog.glsl"
+#include "shadow.glsl"
+#include "weather.glsl"
+#include "ao.glsl"
+#include "block_texture.glsl"
+
+// === ВОДА ===
+uniform int waterPass;               // не 0 — вода отбрасывается (все проходы, кроме полупрозрачного)
+uniform sampler2D reflectionMap;     // мир, отражённый относительно уровня воды
+uniform sampler2D refractionMap;     // мир по другую сторону поверхности воды
+uniform sampler2D waterNormalMap;    // карта нормалей волн (z — вверх)
+uniform float time;
+uniform vec2 screenSize;
+uniform vec3 waterDeepColor;
+uniform float waterTint;
+uniform float waterDistortion;
+uniform float waterWaveScale;
+uniform float waterWaveSpeed;
+uniform bool translucentOnly;        // только полупрозрачное (вода) поверх отложенного освещения
+
+// Нормаль волны: две прокручиваемые в разные стороны выборки карты нормалей.
+// Волнуется только верхняя грань воды, у боковых граней остаётся геометрическая нормаль
+vec3 waveNormal(vec3 N)
+{
+    if (N.y < 0.5) {
+        return N;
+    }
+    vec2 uv = fragPos.xz * waterWaveScale;
+    vec3 n0 = texture(waterNormalMap, uv + vec2(time, time * 0.7) * waterWaveSpeed).rgb * 2.0 - 1.0;
+    vec3 n1 = texture(waterNormalMap, uv * 1.7 - vec2(time * 0.6, -time) * waterWaveSpeed).rgb * 2.0 - 1.0;
+    vec3 n = n0 + n1;
+    return normalize(vec3(n.x, n.z, n.y));
+}
+
+vec3 shadeWater(vec3 N, vec3 L, vec3 V, float shadow)
+{
+    vec3 W = waveNormal(N);
+
+    // Экранные координаты фрагмента; отражение перевёрнуто по вертикали
+    vec2 screenUV = gl_FragCoord.xy / screenSize;
+    vec2 distortion = W.xz * waterDistortion;
+    vec3 refraction = texture(refractionMap, clamp(screenUV + distortion, 0.001, 0.999)).rgb;
+    vec3 reflection = texture(reflectionMap, clamp(vec2(screenUV.x, 1.0 - screenUV.y) + distortion, 0.001, 0.999)).rgb;
+
+    refraction = mix(refraction, waterDeepColor, waterTint);
+    if (underwater) {
+        return refraction; // снизу поверхность только пропускает свет
+    }
+
+    // Френель (аппроксимация Шлика, F0 воды ≈ 0.02)
+    float cosTheta = max(dot(V, W), 0.0);
+    float fresnel = 0.02 + 0.98 * pow(1.0 - cosTheta, 5.0);
+    vec3 color = mix(refraction, reflection, fresnel);
+
+    // Солнечный блик по волнам
+    vec3 H = normalize(L + V);
+    float spec = pow(max(dot(W, H), 0.0), 256.0);
+    return color + (1.0 - shadow) * spec * lightColor;
+}
+
+
+void main()
+{
+    // (1) Освещение
+    vec3 N = normalize(fragNormal);
+    vec3 L = normalize(lightDir);
+    vec3 V = normalize(viewPos - fragPos);
+
+    // Мокрая поверхность темнее и глаже: блик ярче и уже
+    float wet = fragMaterial > 0.5 ? 0.0 : surfaceWetness(fragPos, N);
+    vec3 albedo = fragColor * blockTexture(fragTexCoord) * (1.0 - 0.4 * wet);
+
+    // Ambient, затенённый SSAO в углах и у соседней геометрии
+    vec3 ambient = ambientColor * albedo * ambientOcclusion(gl_FragCoord.xy / screenSize);
+
+    // Diffuse
+    float diff = max(dot(N, L), 0.0);
+    vec3 diffuse = diff * lightColor * albedo;
+
+    // Specular (Blinn-Phong)
+    vec3 H = normalize(L + V);
+    float specAngle = max(dot(N, H), 0.0);
+    float spec = pow(specAngle, mix(shininess, 128.0, wet));
+    vec3 specular = (specularStrength + 1.5 * wet) * spec * lightColor;
+
+    // (2) Тени
+    float shadow = calculateShadow(fragPos, fragViewDepth, N, L);
+    vec3 lightingColor = ambient + (1.0 - shadow) * (diffuse + specular);
+
+    // (3) Вода: проективная выборка отражения и преломления, волны из карты нормалей, Френель
+    if (isWater(fragMaterial)) {
+        if (waterPass != 0) {
+            discard; // воду рисует только проход полупрозрачного
+        }
+        outputColor = vec4(applyFog(shadeWater(N, L, V, shadow), fragDist), 1.0);
+        return;
+    }
+    if (translucentOnly) {
+        discard; // непрозрачное уже нарисовано отложенным проходом
+    }
+
+    // Светящиеся блоки освещение не меняет
+    if (isEmissive(fragMaterial)) {
+        outputColor = vec4(applyFog(fragColor * blockTexture(fragTexCoord) * emissiveStrength, fragDist), 1.0);
+        return;
+    }
+
+    // Не вода — обычный Blinn-Phong с тенями
+    // Туман
+    vec3 finalColor = applyFog(lightingColor, fragDist);
+
+    outputColor = vec4(finalColor, 1.0);
+}
@@ -0,0 +1,41 @@
+#version 410 core
+
+layout(location = 0) in vec3 inPosition;
+layout(location = 1) in vec3 inNormal;   
+layout(location = 2) in vec3 inColor;    
+layout(location = 3) in vec3 inChunkOffset; // смещение чанка при multi-draw (иначе 0)
+layout(location = 4) in float inMaterial;   // 0 — непрозрачный блок, 1 — вода
+layout(location = 5) in vec3 inTexCoord;    // координаты в блоках и слой текстуры
+
+out vec3 fragPos;         
+out vec3 fragNormal;      
+out vec3 fragColor;       
+out vec3 fragTexCoord;
+out float fragDist;       
+out float fragViewDepth;  // глубина в пространстве камеры — по ней выбирается каскад теней
+flat out float fragMaterial;
+
+#include "frame.glsl"
+uniform mat4 model;
+uniform vec4 clipPlane; // плоскость отсечения проходов отражения и преломления воды
+
+void main()
+{
+    vec4 worldPos = model * vec4(inPosition + inChunkOffset, 1.0);
+    fragPos = worldPos.xyz;
+
+    // Преобразуем нормаль (учитывая модельное преобразование)
+    fragNormal = mat3(transpose(inverse(model))) * inNormal;
+
+    fragColor = inColor;
+    fragTexCoord = inTexCoord;
+
+    vec4 viewPos = view * worldPos;
+    fragDist = length(viewPos.xyz);
+    fragViewDepth = -viewPos.z;
+    fragMaterial = inMaterial;
+
+    gl_ClipDistance[0] = dot(worldPos, clipPlane);
+
+    gl_Position = projection * viewPos;
+}
@@ -1,64 +1,91 @@
-package main
-
-import (
-	"engine/src/config"
-	"engine/src/mainloop"
-	"engine/src/player"
-	"engine/src/render"
-	"engine/src/windows"
-	"engine/src/workers"
-	"engine/src/world"
-	"runtime"
-
-	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
-)
-
-var (
-	// frameCount int
-	// prevTime       = float64(0)
-
-	Config = config.LoadConfig("config.json")
-)
-
-func init() {
-	runtime.LockOSThread()
-}
-
-func main() {
-
-	window := windows.InitWindow(Config)
-	defer glfw.Terminate()
-
-	gl.Enable(gl.DEPTH_TEST)
-
-	// Инициализируем шейдеры
-	renderProgram := render.InitShaders()
-	depthProgram := render.InitDepthShader()
-	textProgram := render.InitTextShader()
-	crosshairProgram := render.InitCrosshairShader()
-
-	// Создаём FBO и текстуру для карты теней
-	render.CreateDepthMap(Config)
-
-	// Создаём FBO и текстуру для отражений
-	render.CreateReflectionFBO(Config)
-
-	// Настраиваем мир и камеру
-	worldObj := world.NewWorld(Config.ChunkX, Config.ChunkY, Config.ChunkZ)
-	cameraObj := player.NewCamera(mgl32.Vec3{0, 120, 0})
-
-	chunkGenCh := make(chan [2]int, 100)
-	chunkDelCh := make(chan [2]int, 1000000)
-	vramGCCh := make(chan [3]uint32, 1000000)
-	workers.UpdateWorld(worldObj, cameraObj, chunkGenCh, chunkDelCh, Config)
-	for i := 0; i < Config.NumWorkers; i++ {
-		workers.ChunkCreatorWorker(worldObj, chunkGenCh, chunkDelCh, vramGCCh, Config)
-		workers.ChunkDeleterWorker(worldObj, chunkGenCh, chunkDelCh, vramGCCh)
-	}
-	workers.InitMouseHandler(window, cameraObj)
-	workers.InitPhysicsHandler(cameraObj, worldObj)
-
-	mainloop.RunMainLoop(window, renderProgram, depthProgram, textProgram, crosshairProgram, Config, worldObj, cameraObj, vramGCCh)
-}
+package main
+
+import (
+	"embed"
+	"engine/src/assets"
+	"engine/src/config"
+	"engine/src/mainloop"
+	"engine/src/player"
+	"engine/src/profiler"
+	"engine/src/render"
+	"engine/src/windows"
+	"engine/src/workers"
+	"engine/src/world"
+	"io/fs"
+	"runtime"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var (
+	// frameCount int
+	// prevTime       = float64(0)
+
+	Config = config.LoadConfig("config.json")
+
+	// Встроенные копии шейдеров и шрифта: запасной вариант, если их нет в AssetsDir
+	//go:embed assets/shaders assets/fonts
+	builtinAssets embed.FS
+)
+
+func init() {
+	runtime.LockOSThread()
+}
+
+func main() {
+
+	window := windows.InitWindow(Config)
+	defer glfw.Terminate()
+
+	gl.Enable(gl.DEPTH_TEST)
+	render.InitMultiDraw(Config)
+	profiler.Init()
+
+	// Ресурсы: базовая папка и ресурс-паки поверх неё
+	embedded, _ := fs.Sub(builtinAssets, "assets")
+	assets.Init(Config, embedded)
+	render.CreateHUDFont(Config)
+
+	// Инициализируем шейдеры
+	renderProgram := render.InitShaders()
+	depthProgram := render.InitDepthShader()
+	textProgram := render.InitTextShader()
+	crosshairProgram := render.InitCrosshairShader()
+
+	// Создаём FBO и текстуру для карты теней
+	render.CreateDepthMap(Config)
+
+	// Цели отрисовки воды (отражение, преломление) и карта нормалей волн
+	render.CreateWaterTargets(Config)
+
+	// Шейдер неба, карта высот и частицы осадков
+	render.CreateSky()
+	render.CreateWeather()
+
+	// G-буфер и шейдеры отложенного освещения, если конфиг выбрал этот конвейер
+	render.CreateDeferred(Config)
+
+	// HDR-буфер сцены и цепочка постобработки
+	render.CreatePostProcess(Config)
+
+	// Настраиваем мир и камеру
+	worldObj := world.NewWorld(Config.ChunkX, Config.ChunkY, Config.ChunkZ)
+	worldObj.Clock = world.NewClock(Config.DayLength)
+
+	// Описания блоков и их текстуры (до камеры: она берёт блок для установки по имени)
+	render.CreateBlockTextures(worldObj)
+	cameraObj := player.NewCamera(mgl32.Vec3{0, 120, 0}, Config)
+
+	chunkDelCh := make(chan [2]int, 1000000)
+	vramGCCh := make(chan render.ChunkAllocation, 1000000)
+	workers.UpdateWorld(worldObj, cameraObj, chunkDelCh, Config)
+	for i := 0; i < Config.NumWorkers; i++ {
+		workers.ChunkCreatorWorker(worldObj, Config)
+		workers.ChunkDeleterWorker(worldObj, chunkDelCh)
+	}
+	workers.InitPhysicsHandler(cameraObj, worldObj)
+
+	mainloop.RunMainLoop(window, renderProgram, depthProgram, textProgram, crosshairProgram, Config, worldObj, cameraObj, vramGCCh)
+}
@@ -0,0 +1,154 @@
+package assets
+
+import (
+	"engine/src/config"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Ресурсы игры (шейдеры, текстуры, шрифты, описания блоков) читаются из стопки папок:
+// базовой AssetsDir и ресурс-паков ResourcePacks поверх неё. Файл берётся из самого верхнего
+// пака, где он есть, поэтому пак может заменить любой ресурс, не копируя остальные.
+// Имена ресурсов — пути относительно корня пака через "/", например "textures/blocks/dirt.png".
+// Копии базовых шейдеров и шрифтов встроены в бинарник: они читаются, только если файла
+// нет ни в одном паке, в том числе в AssetsDir.
+
+// Как часто проверяются изменения отслеживаемых файлов
+const pollInterval = 500 * time.Millisecond
+
+// watchedFile — файл ресурса, каким он был при последней загрузке
+type watchedFile struct {
+	Name    string
+	Path    string // "" — файла нет ни в одном паке
+	ModTime time.Time
+	Size    int64
+}
+
+// watch — группа файлов одного ресурса и функция его перезагрузки
+type watch struct {
+	Files  []watchedFile
+	Reload func() error
+}
+
+var (
+	roots    []string // папки поиска, сначала самый верхний пак
+	builtin  fs.FS    // встроенные копии базовых ресурсов (может быть nil)
+	watches  = map[string]*watch{}
+	lastPoll time.Time
+)
+
+// Init задаёт стопку паков из конфига и встроенные копии ресурсов
+func Init(Config *config.Config, embedded fs.FS) {
+	builtin = embedded
+	roots = roots[:0]
+	for i := len(Config.ResourcePacks) - 1; i >= 0; i-- {
+		roots = append(roots, Config.ResourcePacks[i])
+	}
+	roots = append(roots, Config.AssetsDir)
+}
+
+// Resolve возвращает путь к файлу ресурса в самом верхнем паке, где он есть
+func Resolve(name string) (string, bool) {
+	for _, root := range roots {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Read читает ресурс из стопки паков, а если его нет ни в одном — встроенную копию
+func Read(name string) ([]byte, error) {
+	path, ok := Resolve(name)
+	if !ok {
+		if data, err := ReadBuiltin(name); err == nil {
+			return data, nil
+		}
+		return nil, fmt.Errorf("%s: not found in %v", name, roots)
+	}
+	return os.ReadFile(path)
+}
+
+// ReadBuiltin читает встроенную копию ресурса, минуя паки
+func ReadBuiltin(name string) ([]byte, error) {
+	if builtin == nil {
+		return nil, fmt.Errorf("%s: no built-in copy", name)
+	}
+	return fs.ReadFile(builtin, name)
+}
+
+// Exists — есть ли ресурс хотя бы в одном паке (встроенные копии не считаются)
+func Exists(name string) bool {
+	_, ok := Resolve(name)
+	return ok
+}
+
+// Watch отслеживает файлы ресурса key: при изменении, появлении или удалении любого из них
+// (в том числе при перекрытии файлом из более верхнего пака) вызывается reload.
+// Повторный вызов с тем же key заменяет набор файлов. reload при ошибке должен оставить
+// прежнюю версию ресурса — ошибка только печатается.
+func Watch(key string, names []string, reload func() error) {
+	w := &watch{Reload: reload}
+	for _, name := range names {
+		w.Files = append(w.Files, stat(name))
+	}
+	watches[key] = w
+}
+
+// Unwatch прекращает отслеживание ресурса key
+func Unwatch(key string) {
+	delete(watches, key)
+}
+
+func stat(name string) watchedFile {
+	file := watchedFile{Name: name}
+	if path, ok := Resolve(name); ok {
+		if info, err := os.Stat(path); err == nil {
+			file.Path, file.ModTime, file.Size = path, info.ModTime(), info.Size()
+		}
+	}
+	return file
+}
+
+// Poll перезагружает изменившиеся ресурсы. Вызывается каждый кадр из GL-потока
+// (перезагрузка создаёт GL-объекты), сами файлы проверяются раз в pollInterval.
+func Poll() {
+	if time.Since(lastPoll) < pollInterval {
+		return
+	}
+	lastPoll = time.Now()
+
+	// Ключи по порядку: описания блоков перезагружаются до текстур, которые от них зависят
+	keys := make([]string, 0, len(watches))
+	for key := range watches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		w, ok := watches[key]
+		if !ok {
+			continue // снят перезагрузкой другого ресурса
+		}
+		changed := false
+		for i, file := range w.Files {
+			if current := stat(file.Name); current != file {
+				w.Files[i] = current
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := w.Reload(); err != nil {
+			fmt.Printf("Reload %s failed, keeping previous version: %v\n", key, err)
+		} else {
+			fmt.Println("Reloaded", key)
+		}
+	}
+}
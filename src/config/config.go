@@ -15,19 +15,145 @@ type Config struct {
 	Height              int     `json:"Height"`
 	Title               string  `json:"Title"`
 	ChunkDist           int     `json:"ChunkDist"`
+	LODDistances        []int   `json:"LODDistances"`     // с какого расстояния в чанках меш упрощается в 2, 4 и 8 раз; пусто — без LOD
+	OcclusionCulling    bool    `json:"OcclusionCulling"` // не рисовать чанки, скрытые рельефом (граф видимости секций)
 	NumWorkers          int     `json:"NumWorkers"`
 	ChunkX              int     `json:"ChunkX"`
 	ChunkY              int     `json:"ChunkY"`
 	ChunkZ              int     `json:"ChunkZ"`
 	FogStartLoc         float32 `json:"FogStartLoc"`
 	FogEndLoc           float32 `json:"FogEndLoc"`
-	ShadowDist          float32 `json:"ShadowDist"`
-	ShadowHeight        int32   `json:"ShadowHeight"`
-	ShadowWidth         int32   `json:"ShadowWidth"`
+	ShadowDist          float32 `json:"ShadowDist"`        // дальность теней (конец последнего каскада)
+	ShadowCascades      int     `json:"ShadowCascades"`    // число каскадов теней (1–4)
+	ShadowResolution    int32   `json:"ShadowResolution"`  // размер карты теней каждого каскада
+	ShadowSplitLambda   float32 `json:"ShadowSplitLambda"` // 0 — равномерное разбиение, 1 — логарифмическое
 	WarpScale           float64 `json:"WarpScale"`
 	WarpAmp             float64 `json:"WarpAmp"`
 	MaxTerrainHeight    float64 `json:"MaxTerrainHeight"`
 	SeaLevel            float64 `json:"SeaLevel"`
+
+	GamepadDeadZone         float32 `json:"GamepadDeadZone"`
+	GamepadCurve            float32 `json:"GamepadCurve"`
+	GamepadLookSpeed        float64 `json:"GamepadLookSpeed"`
+	GamepadTriggerThreshold float32 `json:"GamepadTriggerThreshold"`
+	MouseSensitivity        float32 `json:"MouseSensitivity"`
+	MouseSmoothing          float32 `json:"MouseSmoothing"`
+	MouseInvertY            bool    `json:"MouseInvertY"`
+
+	FOV                 float32 `json:"FOV"`
+	NearPlane           float32 `json:"NearPlane"`
+	FarPlane            float32 `json:"FarPlane"`
+	ZoomFOV             float32 `json:"ZoomFOV"`
+	SprintFOVBoost      float32 `json:"SprintFOVBoost"`
+	SprintMultiplier    float32 `json:"SprintMultiplier"`
+	ThirdPersonDistance float32 `json:"ThirdPersonDistance"`
+
+	WaterResolutionScale float32 `json:"WaterResolutionScale"` // разрешение отражения/преломления воды от окна
+
+	UploadBudgetKB int     `json:"UploadBudgetKB"` // максимум загрузки мешей в видеопамять за кадр
+	UploadBudgetMs float64 `json:"UploadBudgetMs"` // максимум времени на загрузку мешей за кадр
+
+	DayLength          int64   `json:"DayLength"`          // длина суток в тиках (20 тиков в секунду)
+	DaysPerYear        int     `json:"DaysPerYear"`        // длина года в сутках для смены сезонов, 0 — без сезонов
+	Latitude           float64 `json:"Latitude"`           // широта мира в градусах: высота солнца и длина дня
+	NightAmbient       float32 `json:"NightAmbient"`       // рассеянный свет безлунной ночью
+	MoonlightIntensity float32 `json:"MoonlightIntensity"` // яркость лунного света
+
+	PrecipitationParticles int     `json:"PrecipitationParticles"` // частиц дождя/снега при полной силе осадков
+	PrecipitationRadius    float32 `json:"PrecipitationRadius"`    // радиус области осадков вокруг камеры
+	SnowAccumulationRate   float64 `json:"SnowAccumulationRate"`   // слоёв снега в секунду в сильный снегопад
+
+	RenderPipeline string `json:"RenderPipeline"` // "forward" или "deferred" (G-буфер, точечные источники света)
+	MaxPointLights int    `json:"MaxPointLights"` // максимум точечных источников в кадре (отложенный конвейер)
+
+	PostProcess      bool    `json:"PostProcess"`      // сцена рисуется в HDR-буфер и проходит цепочку постобработки
+	ToneMapping      bool    `json:"ToneMapping"`      // экспозиция и тональная кривая ACES
+	Exposure         float32 `json:"Exposure"`         // множитель яркости перед тональной кривой
+	Bloom            bool    `json:"Bloom"`            // свечение ярких участков (солнце, светящиеся блоки)
+	BloomThreshold   float32 `json:"BloomThreshold"`   // яркость, с которой начинается свечение
+	BloomIntensity   float32 `json:"BloomIntensity"`   // сила свечения
+	FXAA             bool    `json:"FXAA"`             // сглаживание краёв
+	ColorGrading     bool    `json:"ColorGrading"`     // цветокоррекция по таблице ColorLUT
+	ColorLUT         string  `json:"ColorLUT"`         // PNG-полоса N²×N (N ячеек по синему слева направо)
+	Vignette         bool    `json:"Vignette"`         // затемнение к краям кадра
+	VignetteStrength float32 `json:"VignetteStrength"` // 0 — нет, 1 — углы чёрные
+
+	SSAO               bool    `json:"SSAO"`               // затенение ambient в углах и у соседней геометрии
+	SSAOHalfResolution bool    `json:"SSAOHalfResolution"` // считать затенение в половинном разрешении
+	SSAORadius         float32 `json:"SSAORadius"`         // радиус полусферы выборок в блоках
+	SSAOIntensity      float32 `json:"SSAOIntensity"`      // степень, в которую возводится затенение
+	SSAOSamples        int     `json:"SSAOSamples"`        // выборок на пиксель (до 64)
+	SSAODebug          bool    `json:"SSAODebug"`          // показывать затенение вместо сцены
+
+	AssetsDir     string   `json:"AssetsDir"`     // базовые ресурсы: шейдеры, текстуры, шрифты, описания блоков
+	ResourcePacks []string `json:"ResourcePacks"` // паки поверх AssetsDir, последний важнее; файлы перезагружаются на лету
+
+	TextSDF bool `json:"TextSDF"` // текст HUD из поля расстояний: один атлас, чёткие края при любом кегле
+}
+
+// DefaultConfig возвращает значения по умолчанию для полей,
+// которые могут отсутствовать в config.json
+func DefaultConfig() Config {
+	return Config{
+		GamepadDeadZone:         0.15,
+		GamepadCurve:            2.0,
+		GamepadLookSpeed:        180.0,
+		GamepadTriggerThreshold: 0.5,
+		MouseSensitivity:        0.05,
+
+		FOV:                 60.0,
+		NearPlane:           0.1,
+		FarPlane:            3000.0,
+		ZoomFOV:             20.0,
+		SprintFOVBoost:      10.0,
+		SprintMultiplier:    1.5,
+		ThirdPersonDistance: 4.0,
+
+		ShadowCascades:    4,
+		ShadowResolution:  2048,
+		ShadowSplitLambda: 0.75,
+
+		WaterResolutionScale: 0.5,
+
+		LODDistances:     []int{10, 18, 26},
+		OcclusionCulling: true,
+
+		UploadBudgetKB: 4096,
+		UploadBudgetMs: 2.0,
+
+		DayLength:          24000,
+		DaysPerYear:        96,
+		Latitude:           45,
+		NightAmbient:       0.04,
+		MoonlightIntensity: 0.2,
+
+		PrecipitationParticles: 20000,
+		PrecipitationRadius:    48,
+		SnowAccumulationRate:   20,
+
+		RenderPipeline: "forward",
+		MaxPointLights: 512,
+
+		PostProcess:      true,
+		ToneMapping:      true,
+		Exposure:         1.0,
+		Bloom:            true,
+		BloomThreshold:   1.0,
+		BloomIntensity:   0.5,
+		FXAA:             true,
+		Vignette:         true,
+		VignetteStrength: 0.3,
+
+		SSAO:               true,
+		SSAOHalfResolution: true,
+		SSAORadius:         0.75,
+		SSAOIntensity:      1.5,
+		SSAOSamples:        16,
+
+		AssetsDir: "assets",
+
+		TextSDF: false,
+	}
 }
 
 func LoadConfigFromFile(filePath string) (*Config, error) {
@@ -45,7 +171,7 @@ func LoadConfigFromFile(filePath string) (*Config, error) {
 	}
 
 	// Парсим JSON в структуру
-	var config Config
+	config := DefaultConfig()
 	if err := json.Unmarshal(bytes, &config); err != nil {
 		return nil, fmt.Errorf("не удалось распарсить JSON: %w", err)
 	}
@@ -0,0 +1,258 @@
+package console
+
+import (
+	"engine/src/config"
+	"engine/src/player"
+	"engine/src/profiler"
+	"engine/src/world"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Context — то, над чем работают команды: мир, игрок и настройки
+type Context struct {
+	World  *world.World
+	Player *player.Camera
+	Config *config.Config
+}
+
+// Command — команда консоли: подсказка и обработчик аргументов
+type Command struct {
+	Usage string
+	Run   func(args []string, ctx Context) (string, error)
+}
+
+// commands — все команды консоли по имени. Заполняется в init:
+// обработчики сами обращаются к таблице (help, подсказки)
+var commands map[string]Command
+
+func init() {
+	commands = map[string]Command{
+		"time": {
+			Usage: "time [set <hh:mm|ticks|sunrise|day|noon|sunset|night|midnight> | add <ticks> | freeze | unfreeze]",
+			Run:   timeCommand,
+		},
+		"weather": {
+			Usage: "weather [clear|rain|thunder|snow [seconds]]",
+			Run:   weatherCommand,
+		},
+		"block": {
+			Usage: "block [name]",
+			Run:   blockCommand,
+		},
+		"ssao": {
+			Usage: "ssao [on|off|debug|half|full]",
+			Run:   ssaoCommand,
+		},
+		"profile": {
+			Usage: "profile [dump <file.csv|file.json>]",
+			Run:   profileCommand,
+		},
+		"help": {
+			Usage: "help",
+			Run:   helpCommand,
+		},
+	}
+}
+
+// Execute разбирает строку и выполняет команду. Ведущий "/" необязателен.
+func Execute(line string, ctx Context) (string, error) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "/"))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	cmd, ok := commands[strings.ToLower(fields[0])]
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try help", fields[0])
+	}
+	return cmd.Run(fields[1:], ctx)
+}
+
+func helpCommand(_ []string, _ Context) (string, error) {
+	usages := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		usages = append(usages, cmd.Usage)
+	}
+	sort.Strings(usages)
+	return strings.Join(usages, "; "), nil
+}
+
+// Именованные моменты суток для time set
+var namedTimes = map[string][2]int{
+	"sunrise":  {6, 0},
+	"day":      {8, 0},
+	"noon":     {12, 0},
+	"sunset":   {18, 0},
+	"night":    {21, 0},
+	"midnight": {0, 0},
+}
+
+func timeCommand(args []string, ctx Context) (string, error) {
+	clock := ctx.World.Clock
+	if len(args) == 0 {
+		return clock.Now().String(), nil
+	}
+	usage := fmt.Errorf("usage: %s", commands["time"].Usage)
+
+	switch strings.ToLower(args[0]) {
+	case "set":
+		if len(args) != 2 {
+			return "", usage
+		}
+		tick, err := parseTimeOfDay(args[1], clock)
+		if err != nil {
+			return "", err
+		}
+		clock.SetTimeOfDay(tick)
+	case "add":
+		if len(args) != 2 {
+			return "", usage
+		}
+		ticks, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("bad tick count %q", args[1])
+		}
+		clock.Add(ticks)
+	case "freeze":
+		clock.SetFrozen(true)
+	case "unfreeze":
+		clock.SetFrozen(false)
+	default:
+		return "", usage
+	}
+	return clock.Now().String(), nil
+}
+
+// parseTimeOfDay понимает имя момента суток, "чч:мм" и число тиков от полуночи
+func parseTimeOfDay(s string, clock *world.Clock) (int64, error) {
+	if hm, ok := namedTimes[strings.ToLower(s)]; ok {
+		return clock.TicksAt(hm[0], hm[1]), nil
+	}
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		hours, errH := strconv.Atoi(h)
+		minutes, errM := strconv.Atoi(m)
+		if errH != nil || errM != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+			return 0, fmt.Errorf("bad time %q, expected hh:mm", s)
+		}
+		return clock.TicksAt(hours, minutes), nil
+	}
+	ticks, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ticks < 0 {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	return ticks, nil
+}
+
+// Длительность погоды, заданной командой без явного срока
+const defaultWeatherSeconds = 300
+
+func weatherCommand(args []string, ctx Context) (string, error) {
+	weather := ctx.World.Weather
+	if len(args) == 0 {
+		return weather.Now().String(), nil
+	}
+	if len(args) > 2 {
+		return "", fmt.Errorf("usage: %s", commands["weather"].Usage)
+	}
+	kind, err := world.ParseWeatherKind(strings.ToLower(args[0]))
+	if err != nil {
+		return "", err
+	}
+	seconds := float64(defaultWeatherSeconds)
+	if len(args) == 2 {
+		seconds, err = strconv.ParseFloat(args[1], 64)
+		if err != nil || seconds <= 0 {
+			return "", fmt.Errorf("bad duration %q", args[1])
+		}
+	}
+	weather.Set(kind, seconds)
+	return fmt.Sprintf("weather set to %s for %.0f s", kind, seconds), nil
+}
+
+// blockNames — имена блоков для установки в алфавитном порядке
+func blockNames() []string {
+	names := make([]string, 0, len(world.NamedBlocks))
+	for name := range world.NamedBlocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func blockCommand(args []string, ctx Context) (string, error) {
+	if len(args) == 0 {
+		for name, block := range world.NamedBlocks {
+			if block == ctx.Player.HeldBlock {
+				return "holding " + name, nil
+			}
+		}
+		return fmt.Sprintf("holding block %d", ctx.Player.HeldBlock.Id), nil
+	}
+	if len(args) > 1 {
+		return "", fmt.Errorf("usage: %s", commands["block"].Usage)
+	}
+	name := strings.ToLower(args[0])
+	block, ok := world.NamedBlocks[name]
+	if !ok {
+		return "", fmt.Errorf("unknown block %q, try %s", args[0], strings.Join(blockNames(), "|"))
+	}
+	ctx.Player.HeldBlock = block
+	return "holding " + name, nil
+}
+
+// ssaoCommand переключает затенение SSAO: debug показывает его вместо сцены (повторно — выключает),
+// half и full меняют разрешение
+func ssaoCommand(args []string, ctx Context) (string, error) {
+	cfg := ctx.Config
+	if len(args) > 1 {
+		return "", fmt.Errorf("usage: %s", commands["ssao"].Usage)
+	}
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			cfg.SSAO = true
+		case "off":
+			cfg.SSAO = false
+			cfg.SSAODebug = false
+		case "debug":
+			cfg.SSAODebug = !cfg.SSAODebug
+			cfg.SSAO = cfg.SSAO || cfg.SSAODebug
+		case "half":
+			cfg.SSAOHalfResolution = true
+		case "full":
+			cfg.SSAOHalfResolution = false
+		default:
+			return "", fmt.Errorf("usage: %s", commands["ssao"].Usage)
+		}
+	}
+	if !cfg.SSAO {
+		return "ssao off", nil
+	}
+	state := "ssao on, full resolution"
+	if cfg.SSAOHalfResolution {
+		state = "ssao on, half resolution"
+	}
+	if cfg.SSAODebug {
+		state += ", debug view"
+	}
+	return state, nil
+}
+
+// profileCommand показывает сводку времени кадра или выгружает историю профилировщика
+// в CSV либо трассу Chrome (по расширению файла)
+func profileCommand(args []string, ctx Context) (string, error) {
+	switch {
+	case len(args) == 0:
+		s := profiler.Summarize()
+		return fmt.Sprintf("%d frames: avg %.2f ms, p50 %.2f, p95 %.2f, p99 %.2f, max %.2f",
+			s.Frames, s.AvgMillis, s.P50, s.P95, s.P99, s.Max), nil
+	case len(args) == 2 && strings.ToLower(args[0]) == "dump":
+		if err := profiler.Dump(args[1]); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("profile written to %s", args[1]), nil
+	}
+	return "", fmt.Errorf("usage: %s", commands["profile"].Usage)
+}
@@ -0,0 +1,113 @@
+package console
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Сколько строк вывода хранить и сколько секунд показывать их после закрытия консоли
+const (
+	maxMessages    = 8
+	messageTimeout = 8 * time.Second
+)
+
+// Message — строка вывода консоли
+type Message struct {
+	Text  string
+	Error bool
+	At    time.Time
+}
+
+// Console — строка ввода команд. Символы приходят из колбэка GLFW,
+// открытие, отправка и закрытие — из действий input в главном цикле.
+type Console struct {
+	Open bool
+
+	mu       sync.Mutex
+	line     []rune
+	messages []Message
+}
+
+// Attach создаёт консоль и подписывает её на ввод символов окна.
+// Колбэки GLFW вызываются в главном потоке внутри glfw.PollEvents.
+func Attach(window *glfw.Window) *Console {
+	c := &Console{}
+	window.SetCharCallback(func(_ *glfw.Window, char rune) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.Open {
+			c.line = append(c.line, char)
+		}
+	})
+	window.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, _ int, action glfw.Action, _ glfw.ModifierKey) {
+		if key != glfw.KeyBackspace || action == glfw.Release {
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.Open && len(c.line) > 0 {
+			c.line = c.line[:len(c.line)-1]
+		}
+	})
+	return c
+}
+
+// SetOpen открывает или закрывает строку ввода; набранный текст сбрасывается
+func (c *Console) SetOpen(open bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Open = open
+	c.line = c.line[:0]
+}
+
+// Line возвращает набранную строку
+func (c *Console) Line() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.line)
+}
+
+// Submit выполняет набранную команду и закрывает консоль
+func (c *Console) Submit(ctx Context) {
+	line := strings.TrimSpace(c.Line())
+	c.SetOpen(false)
+	if line == "" {
+		return
+	}
+	c.Print("> "+line, false)
+	out, err := Execute(line, ctx)
+	if err != nil {
+		c.Print(err.Error(), true)
+		return
+	}
+	if out != "" {
+		c.Print(out, false)
+	}
+}
+
+// Print добавляет строку в вывод консоли
+func (c *Console) Print(text string, isError bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, Message{Text: text, Error: isError, At: time.Now()})
+	if len(c.messages) > maxMessages {
+		c.messages = c.messages[len(c.messages)-maxMessages:]
+	}
+}
+
+// Messages возвращает строки вывода для отрисовки: все при открытой консоли,
+// иначе только недавние
+func (c *Console) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []Message
+	for _, m := range c.messages {
+		if c.Open || time.Since(m.At) < messageTimeout {
+			out = append(out, m)
+		}
+	}
+	return out
+}
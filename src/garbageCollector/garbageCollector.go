@@ -1,16 +1,17 @@
 package garbageCollector
 
 import (
-	"github.com/go-gl/gl/v4.1-core/gl"
+	"engine/src/render"
 )
 
-func VramGC(vramGCCh chan [3]uint32, Cunt_ch *int) {
+// VramGC возвращает в пул общих буферов участки выгруженных и перестроенных чанков
+func VramGC(vramGCCh chan render.ChunkAllocation, Cunt_ch *int) {
 	for len(vramGCCh) > 0 {
 		x := <-vramGCCh
-		gl.DeleteVertexArrays(1, &x[0])
-		gl.DeleteBuffers(1, &x[1])
-		gl.DeleteBuffers(1, &x[2])
+		render.FreeChunkAllocation(x)
 		//println(*Cunt_ch)
-		*Cunt_ch--
+		if x.Unloaded {
+			*Cunt_ch--
+		}
 	}
 }
@@ -0,0 +1,166 @@
+package input
+
+import (
+	"engine/src/config"
+	"slices"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Action — логическое действие игрока, не зависящее от устройства ввода
+type Action int
+
+const (
+	ActionMoveForward Action = iota
+	ActionMoveBack
+	ActionMoveLeft
+	ActionMoveRight
+	ActionJump    // прыжок / подъём в креативе
+	ActionDescend // спуск в креативе
+	ActionSprint
+	ActionZoom
+	ActionToggleView // первое/третье лицо
+	ActionBreak      // сломать блок
+	ActionPlace      // поставить блок
+	ActionToggleCreative
+	ActionToggleHUD
+	ActionToggleInfoPanel
+	ActionToggleWireframe
+	ActionPause  // открыть/закрыть меню паузы
+	ActionMenuUp // навигация по меню
+	ActionMenuDown
+	ActionMenuSelect
+	ActionMenuClick // клик мышью по пункту меню
+	ActionConsole   // открыть строку команд
+
+	ActionCount
+)
+
+// State — состояние ввода за один кадр, собранное со всех устройств
+type State struct {
+	Move    mgl32.Vec2        // X — вбок (вправо +), Y — вперёд (+); длина не больше 1
+	Look    mgl32.Vec2        // поворот взгляда за кадр в градусах: X — yaw, Y — pitch
+	Held    [ActionCount]bool // действие удерживается
+	Pressed [ActionCount]bool // действие нажато именно в этом кадре
+	Cursor  mgl32.Vec2        // позиция курсора в окне (для меню)
+}
+
+// Controller собирает ввод с клавиатуры, мыши и геймпадов в единый State
+type Controller struct {
+	Config   *config.Config
+	Mouse    *Mouse
+	prevHeld [ActionCount]bool
+	latched  [ActionCount]bool // действия, которые игнорируются до отпускания
+}
+
+func NewController(window *glfw.Window, Config *config.Config) *Controller {
+	return &Controller{
+		Config: Config,
+		Mouse:  AttachMouse(window),
+	}
+}
+
+// WaitRelease отключает действия, пока их не отпустят: нажатие, закрывшее меню,
+// не должно продолжиться в игре (например, сломать блок под прицелом)
+func (c *Controller) WaitRelease(actions ...Action) {
+	for _, a := range actions {
+		c.latched[a] = true
+	}
+}
+
+// SharingBinding возвращает действия, у которых есть общая с одним из actions клавиша,
+// кнопка мыши или геймпада: нажатие, выбравшее пункт меню, удерживает и их
+func SharingBinding(actions ...Action) []Action {
+	keys := map[glfw.Key]bool{}
+	mouse := map[glfw.MouseButton]bool{}
+	pad := map[glfw.GamepadButton]bool{}
+	for _, a := range actions {
+		for _, k := range keyBindings[a] {
+			keys[k] = true
+		}
+		for _, b := range mouseButtonsOf(a) {
+			mouse[b] = true
+		}
+		for _, b := range gamepadBindings[a] {
+			pad[b] = true
+		}
+	}
+
+	var shared []Action
+	for a := Action(0); a < ActionCount; a++ {
+		if slices.Contains(actions, a) {
+			continue
+		}
+		found := slices.ContainsFunc(keyBindings[a], func(k glfw.Key) bool { return keys[k] }) ||
+			slices.ContainsFunc(mouseButtonsOf(a), func(b glfw.MouseButton) bool { return mouse[b] }) ||
+			slices.ContainsFunc(gamepadBindings[a], func(b glfw.GamepadButton) bool { return pad[b] })
+		if found {
+			shared = append(shared, a)
+		}
+	}
+	return shared
+}
+
+// mouseButtonsOf — кнопки мыши действия, включая клик по пункту меню
+func mouseButtonsOf(a Action) []glfw.MouseButton {
+	if a == ActionMenuClick {
+		return menuMouseButtons
+	}
+	return mouseBindings[a]
+}
+
+// Update опрашивает устройства и возвращает состояние ввода за кадр.
+// Должен вызываться из главного потока (требование GLFW).
+func (c *Controller) Update(window *glfw.Window, deltaTime float64) State {
+	var state State
+	PollKeyboard(window, &state)
+
+	// Взгляд мышью: смещение, накопленное колбэками курсора за кадр
+	delta := c.Mouse.TakeDelta(c.Config.MouseSmoothing)
+	if c.Mouse.Captured() {
+		dy := -delta.Y() // экранная ось Y направлена вниз
+		if c.Config.MouseInvertY {
+			dy = -dy
+		}
+		state.Look = mgl32.Vec2{delta.X(), dy}.Mul(c.Config.MouseSensitivity)
+	}
+	state.Cursor = c.Mouse.Cursor()
+
+	return c.Feed(state, PollGamepads(), deltaTime)
+}
+
+// Feed дополняет состояние клавиатуры данными геймпадов и вычисляет фронты нажатий.
+// Не обращается к GLFW, поэтому годится для подачи синтетических состояний.
+func (c *Controller) Feed(state State, gamepads []GamepadState, deltaTime float64) State {
+	for _, gp := range gamepads {
+		ApplyGamepad(gp, c.Config, deltaTime, &state)
+	}
+
+	// Цифровые направления (WASD, крестовина) складываем с аналоговым стиком
+	if state.Held[ActionMoveForward] {
+		state.Move[1] += 1
+	}
+	if state.Held[ActionMoveBack] {
+		state.Move[1] -= 1
+	}
+	if state.Held[ActionMoveRight] {
+		state.Move[0] += 1
+	}
+	if state.Held[ActionMoveLeft] {
+		state.Move[0] -= 1
+	}
+	if l := state.Move.Len(); l > 1 {
+		state.Move = state.Move.Mul(1 / l)
+	}
+
+	for a := Action(0); a < ActionCount; a++ {
+		if c.latched[a] {
+			c.latched[a] = state.Held[a]
+			state.Held[a] = false
+		}
+		state.Pressed[a] = state.Held[a] && !c.prevHeld[a]
+	}
+	c.prevHeld = state.Held
+	return state
+}
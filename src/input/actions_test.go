@@ -0,0 +1,181 @@
+package input
+
+import (
+	"engine/src/config"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testConfig() *config.Config {
+	Config := config.DefaultConfig()
+	return &Config
+}
+
+// idlePad — геймпад в покое: стики по центру, курки отпущены (у GLFW это -1)
+func idlePad() GamepadState {
+	var pad GamepadState
+	pad.Axes[glfw.AxisLeftTrigger] = -1
+	pad.Axes[glfw.AxisRightTrigger] = -1
+	return pad
+}
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestStickVectorDeadZoneAndCurve(t *testing.T) {
+	const deadZone, curve = 0.2, 2.0
+	tests := []struct {
+		x, y float32
+		want mgl32.Vec2
+	}{
+		{0, 0, mgl32.Vec2{}},
+		{0.1, 0.1, mgl32.Vec2{}}, // длина 0.14 — внутри мёртвой зоны
+		{0.2, 0, mgl32.Vec2{}},   // ровно на границе
+		{0.6, 0, mgl32.Vec2{0.25, 0}},
+		{0, -1, mgl32.Vec2{0, -1}},
+		{2, 0, mgl32.Vec2{1, 0}}, // за пределами круга — не больше 1
+	}
+	for _, tt := range tests {
+		got := StickVector(tt.x, tt.y, deadZone, curve)
+		if !near(got[0], tt.want[0]) || !near(got[1], tt.want[1]) {
+			t.Errorf("StickVector(%v, %v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	// Направление по диагонали сохраняется, длина идёт по кривой
+	got := StickVector(0.6, 0.6, deadZone, curve)
+	mag := float32(math.Sqrt(0.72))
+	scaled := (mag - deadZone) / (1 - deadZone)
+	if !near(got.Len(), scaled*scaled) || !near(got[0], got[1]) {
+		t.Errorf("StickVector(0.6, 0.6) = %v, want length %v along the diagonal", got, scaled*scaled)
+	}
+
+	// Без кривой ответ линейный
+	if got := StickVector(0.6, 0, deadZone, 0); !near(got[0], 0.5) {
+		t.Errorf("StickVector without curve = %v, want 0.5", got)
+	}
+}
+
+func TestFeedMoveNormalization(t *testing.T) {
+	c := &Controller{Config: testConfig()}
+
+	// Крестовина вперёд и вправо: диагональ длиной 1
+	pad := idlePad()
+	pad.Buttons[glfw.ButtonDpadUp] = true
+	pad.Buttons[glfw.ButtonDpadRight] = true
+	state := c.Feed(State{}, []GamepadState{pad}, 1.0/60)
+	if !near(state.Move.Len(), 1) || !near(state.Move[0], state.Move[1]) {
+		t.Errorf("dpad diagonal Move = %v, want unit diagonal", state.Move)
+	}
+
+	// Клавиша вперёд и стик вперёд до упора не дают скорость больше 1
+	pad = idlePad()
+	pad.Axes[glfw.AxisLeftY] = -1 // ось Y стика у GLFW направлена вниз
+	keys := State{}
+	keys.Held[ActionMoveForward] = true
+	state = c.Feed(keys, []GamepadState{pad}, 1.0/60)
+	if !near(state.Move[0], 0) || !near(state.Move[1], 1) {
+		t.Errorf("key + stick Move = %v, want {0, 1}", state.Move)
+	}
+
+	// Слабый наклон стика без клавиш остаётся аналоговым
+	pad = idlePad()
+	pad.Axes[glfw.AxisLeftX] = 0.6
+	state = c.Feed(State{}, []GamepadState{pad}, 1.0/60)
+	want := StickVector(0.6, 0, c.Config.GamepadDeadZone, c.Config.GamepadCurve)
+	if !near(state.Move[0], want[0]) || state.Move[0] >= 1 {
+		t.Errorf("partial stick Move = %v, want %v", state.Move, want)
+	}
+}
+
+func TestFeedPressedEdges(t *testing.T) {
+	c := &Controller{Config: testConfig()}
+
+	jump := idlePad()
+	jump.Buttons[glfw.ButtonA] = true
+	jump.Axes[glfw.AxisRightTrigger] = 1 // курок до упора — ломать блок
+
+	first := c.Feed(State{}, []GamepadState{jump}, 1.0/60)
+	if !first.Held[ActionJump] || !first.Pressed[ActionJump] {
+		t.Errorf("first frame: Held=%v Pressed=%v, want both", first.Held[ActionJump], first.Pressed[ActionJump])
+	}
+	if !first.Pressed[ActionBreak] {
+		t.Error("first frame: trigger past threshold should press ActionBreak")
+	}
+
+	second := c.Feed(State{}, []GamepadState{jump}, 1.0/60)
+	if !second.Held[ActionJump] || second.Pressed[ActionJump] || second.Pressed[ActionBreak] {
+		t.Errorf("second frame: Held=%v Pressed=%v, want held without a new press",
+			second.Held[ActionJump], second.Pressed[ActionJump])
+	}
+
+	released := c.Feed(State{}, []GamepadState{idlePad()}, 1.0/60)
+	if released.Held[ActionJump] || released.Held[ActionBreak] {
+		t.Error("released frame: actions still held")
+	}
+
+	again := c.Feed(State{}, []GamepadState{jump}, 1.0/60)
+	if !again.Pressed[ActionJump] {
+		t.Error("press after release was not detected")
+	}
+}
+
+func TestWaitReleaseIgnoresHeldAction(t *testing.T) {
+	c := &Controller{Config: testConfig()}
+	click := State{}
+	click.Held[ActionBreak] = true
+	click.Held[ActionMenuClick] = true
+
+	c.Feed(click, nil, 1.0/60)
+	c.WaitRelease(ActionBreak, ActionPlace)
+
+	// Кнопка ещё зажата после закрытия меню — ломать нельзя
+	held := c.Feed(click, nil, 1.0/60)
+	if held.Held[ActionBreak] || held.Pressed[ActionBreak] {
+		t.Error("latched ActionBreak is still reported while the button is held")
+	}
+	if !held.Held[ActionMenuClick] {
+		t.Error("WaitRelease affected an action it was not given")
+	}
+
+	c.Feed(State{}, nil, 1.0/60)
+	again := c.Feed(click, nil, 1.0/60)
+	if !again.Held[ActionBreak] || !again.Pressed[ActionBreak] {
+		t.Error("ActionBreak is not reported after release and a new press")
+	}
+}
+
+func TestMenuSelectWithGamepadDoesNotJump(t *testing.T) {
+	shared := SharingBinding(ActionMenuSelect, ActionMenuClick)
+	for _, want := range []Action{ActionJump, ActionBreak} {
+		if !slices.Contains(shared, want) {
+			t.Errorf("SharingBinding(MenuSelect, MenuClick) = %v, missing %v", shared, want)
+		}
+	}
+	if slices.Contains(shared, ActionPlace) || slices.Contains(shared, ActionMenuSelect) {
+		t.Errorf("SharingBinding(MenuSelect, MenuClick) = %v, has unrelated actions", shared)
+	}
+
+	// «Resume» выбран кнопкой A: она же прыжок
+	c := &Controller{Config: testConfig()}
+	pressA := idlePad()
+	pressA.Buttons[glfw.ButtonA] = true
+	if state := c.Feed(State{}, []GamepadState{pressA}, 1.0/60); !state.Pressed[ActionMenuSelect] {
+		t.Fatal("button A did not select the menu item")
+	}
+	c.WaitRelease(shared...)
+
+	held := c.Feed(State{}, []GamepadState{pressA}, 1.0/60)
+	if held.Held[ActionJump] || held.Pressed[ActionJump] {
+		t.Error("button A held after closing the menu makes the player jump")
+	}
+	c.Feed(State{}, []GamepadState{idlePad()}, 1.0/60)
+	if again := c.Feed(State{}, []GamepadState{pressA}, 1.0/60); !again.Pressed[ActionJump] {
+		t.Error("jump is not reported after releasing and pressing A again")
+	}
+}
@@ -0,0 +1,111 @@
+package input
+
+import (
+	"engine/src/config"
+	"math"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// GamepadState — снимок состояния геймпада в раскладке GLFW gamepad mappings
+type GamepadState struct {
+	Axes    [glfw.AxisLast + 1]float32
+	Buttons [glfw.ButtonLast + 1]bool
+}
+
+// Привязки кнопок геймпада к действиям
+var gamepadBindings = map[Action][]glfw.GamepadButton{
+	ActionMoveForward:     {glfw.ButtonDpadUp},
+	ActionMoveBack:        {glfw.ButtonDpadDown},
+	ActionMenuUp:          {glfw.ButtonDpadUp},
+	ActionMenuDown:        {glfw.ButtonDpadDown},
+	ActionMoveLeft:        {glfw.ButtonDpadLeft},
+	ActionMoveRight:       {glfw.ButtonDpadRight},
+	ActionJump:            {glfw.ButtonA},
+	ActionDescend:         {glfw.ButtonB},
+	ActionSprint:          {glfw.ButtonLeftThumb},
+	ActionZoom:            {glfw.ButtonRightThumb},
+	ActionToggleView:      {glfw.ButtonRightBumper},
+	ActionToggleCreative:  {glfw.ButtonY},
+	ActionToggleInfoPanel: {glfw.ButtonBack},
+	ActionPause:           {glfw.ButtonStart},
+	ActionMenuSelect:      {glfw.ButtonA},
+}
+
+// Привязки курков геймпада к действиям
+var triggerBindings = map[Action]glfw.GamepadAxis{
+	ActionBreak: glfw.AxisRightTrigger,
+	ActionPlace: glfw.AxisLeftTrigger,
+}
+
+// PollGamepads возвращает состояния всех подключённых геймпадов,
+// для которых у GLFW есть маппинг
+func PollGamepads() []GamepadState {
+	var pads []GamepadState
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if !joy.IsGamepad() {
+			continue
+		}
+		gs := joy.GetGamepadState()
+		if gs == nil {
+			continue
+		}
+		pads = append(pads, FromGLFW(gs))
+	}
+	return pads
+}
+
+// FromGLFW переводит состояние GLFW в GamepadState
+func FromGLFW(gs *glfw.GamepadState) GamepadState {
+	var pad GamepadState
+	pad.Axes = gs.Axes
+	for i, b := range gs.Buttons {
+		pad.Buttons[i] = b == glfw.Press
+	}
+	return pad
+}
+
+// ApplyGamepad добавляет в state движение, взгляд и действия с геймпада
+func ApplyGamepad(pad GamepadState, Config *config.Config, deltaTime float64, state *State) {
+	// Левый стик — движение. У GLFW ось Y стика направлена вниз.
+	move := StickVector(pad.Axes[glfw.AxisLeftX], -pad.Axes[glfw.AxisLeftY], Config.GamepadDeadZone, Config.GamepadCurve)
+	state.Move = state.Move.Add(move)
+
+	// Правый стик — взгляд, скорость в градусах в секунду
+	look := StickVector(pad.Axes[glfw.AxisRightX], -pad.Axes[glfw.AxisRightY], Config.GamepadDeadZone, Config.GamepadCurve)
+	state.Look = state.Look.Add(look.Mul(float32(Config.GamepadLookSpeed * deltaTime)))
+
+	for action, buttons := range gamepadBindings {
+		for _, button := range buttons {
+			if pad.Buttons[button] {
+				state.Held[action] = true
+			}
+		}
+	}
+	for action, axis := range triggerBindings {
+		// Курки у GLFW лежат в диапазоне [-1, 1], где -1 — отпущен
+		if (pad.Axes[axis]+1)/2 >= Config.GamepadTriggerThreshold {
+			state.Held[action] = true
+		}
+	}
+}
+
+// StickVector применяет радиальную мёртвую зону и кривую чувствительности к стику.
+// Значение за мёртвой зоной перемасштабируется в [0, 1] и возводится в степень curve,
+// направление стика сохраняется.
+func StickVector(x, y, deadZone, curve float32) mgl32.Vec2 {
+	v := mgl32.Vec2{x, y}
+	mag := v.Len()
+	if mag <= deadZone || mag == 0 {
+		return mgl32.Vec2{}
+	}
+	if mag > 1 {
+		mag = 1
+	}
+	scaled := (mag - deadZone) / (1 - deadZone)
+	if curve > 0 {
+		scaled = float32(math.Pow(float64(scaled), float64(curve)))
+	}
+	return v.Normalize().Mul(scaled)
+}
@@ -0,0 +1,59 @@
+package input
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Привязки клавиш к действиям
+var keyBindings = map[Action][]glfw.Key{
+	ActionMoveForward:     {glfw.KeyW},
+	ActionMoveBack:        {glfw.KeyS},
+	ActionMoveLeft:        {glfw.KeyA},
+	ActionMoveRight:       {glfw.KeyD},
+	ActionJump:            {glfw.KeySpace},
+	ActionDescend:         {glfw.KeyLeftShift},
+	ActionSprint:          {glfw.KeyLeftControl},
+	ActionZoom:            {glfw.KeyC},
+	ActionToggleView:      {glfw.KeyF5},
+	ActionToggleCreative:  {glfw.KeyRightBracket},
+	ActionToggleHUD:       {glfw.KeyF1},
+	ActionToggleInfoPanel: {glfw.KeyF3},
+	ActionToggleWireframe: {glfw.KeyM},
+	ActionPause:           {glfw.KeyEscape},
+	ActionMenuUp:          {glfw.KeyUp},
+	ActionMenuDown:        {glfw.KeyDown},
+	ActionMenuSelect:      {glfw.KeyEnter},
+	ActionConsole:         {glfw.KeyT, glfw.KeySlash},
+}
+
+// Привязки кнопок мыши к действиям
+var mouseBindings = map[Action][]glfw.MouseButton{
+	ActionBreak: {glfw.MouseButtonLeft},
+	ActionPlace: {glfw.MouseButtonRight},
+}
+
+// Кнопки мыши, которые в меню работают как клик по пункту
+var menuMouseButtons = []glfw.MouseButton{glfw.MouseButtonLeft}
+
+// PollKeyboard отмечает в state действия, удерживаемые с клавиатуры и мыши
+func PollKeyboard(window *glfw.Window, state *State) {
+	for action, keys := range keyBindings {
+		for _, key := range keys {
+			if window.GetKey(key) == glfw.Press {
+				state.Held[action] = true
+			}
+		}
+	}
+	for action, buttons := range mouseBindings {
+		for _, button := range buttons {
+			if window.GetMouseButton(button) == glfw.Press {
+				state.Held[action] = true
+			}
+		}
+	}
+	for _, button := range menuMouseButtons {
+		if window.GetMouseButton(button) == glfw.Press {
+			state.Held[ActionMenuClick] = true
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package input
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mouse накапливает перемещения курсора из колбэков GLFW между кадрами
+type Mouse struct {
+	delta    mgl32.Vec2 // накопленное смещение с прошлого кадра
+	smoothed mgl32.Vec2 // сглаженное смещение прошлого кадра
+	lastX    float64
+	lastY    float64
+	hasLast  bool
+	cursor   mgl32.Vec2 // последняя позиция курсора в координатах окна
+	captured bool
+}
+
+// AttachMouse подписывается на перемещения курсора окна.
+// Колбэки GLFW вызываются в главном потоке внутри glfw.PollEvents.
+func AttachMouse(window *glfw.Window) *Mouse {
+	m := &Mouse{}
+	window.SetCursorPosCallback(func(_ *glfw.Window, xpos, ypos float64) {
+		m.cursor = mgl32.Vec2{float32(xpos), float32(ypos)}
+		if !m.captured {
+			return
+		}
+		if m.hasLast {
+			m.delta = m.delta.Add(mgl32.Vec2{float32(xpos - m.lastX), float32(ypos - m.lastY)})
+		}
+		m.lastX, m.lastY = xpos, ypos
+		m.hasLast = true
+	})
+	m.SetCaptured(window, true)
+	return m
+}
+
+// SetCaptured захватывает курсор для управления взглядом или отпускает его (меню паузы)
+func (m *Mouse) SetCaptured(window *glfw.Window, captured bool) {
+	m.captured = captured
+	m.Reset()
+	if captured {
+		window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+		if glfw.RawMouseMotionSupported() {
+			window.SetInputMode(glfw.RawMouseMotion, glfw.True)
+		}
+	} else {
+		if glfw.RawMouseMotionSupported() {
+			window.SetInputMode(glfw.RawMouseMotion, glfw.False)
+		}
+		window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
+}
+
+// Reset отбрасывает накопленное смещение, чтобы смена режима курсора не давала рывка
+func (m *Mouse) Reset() {
+	m.delta = mgl32.Vec2{}
+	m.smoothed = mgl32.Vec2{}
+	m.hasLast = false
+}
+
+// Captured сообщает, управляет ли сейчас мышь взглядом
+func (m *Mouse) Captured() bool {
+	return m.captured
+}
+
+// Cursor возвращает позицию курсора в координатах окна
+func (m *Mouse) Cursor() mgl32.Vec2 {
+	return m.cursor
+}
+
+// TakeDelta забирает смещение, накопленное за кадр.
+// smoothing в [0, 1) задаёт долю прошлого кадра в экспоненциальном сглаживании.
+func (m *Mouse) TakeDelta(smoothing float32) mgl32.Vec2 {
+	raw := m.delta
+	m.delta = mgl32.Vec2{}
+	if smoothing <= 0 {
+		m.smoothed = raw
+		return raw
+	}
+	if smoothing > 0.95 {
+		smoothing = 0.95
+	}
+	m.smoothed = raw.Mul(1 - smoothing).Add(m.smoothed.Mul(smoothing))
+	return m.smoothed
+}
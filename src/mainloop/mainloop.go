@@ -1,69 +1,123 @@
-package mainloop
-
-import (
-	"engine/src/config"
-	"engine/src/garbageCollector"
-	"engine/src/player"
-	"engine/src/render"
-	"engine/src/world"
-	"math"
-	"time"
-
-	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
-)
-
-var (
-	timeOfDay = float64(0)
-)
-
-func RunMainLoop(
-	window *glfw.Window,
-	renderProgram, depthProgram, textProgram, crosshairProgram uint32,
-	config *config.Config,
-	worldObj *world.World,
-	playerObj *player.Camera,
-	vramGCCh chan [3]uint32,
-) {
-	lastFrame := time.Now()
-
-	// Каналы для асинхронной генерации/удаления чанков
-
-	lightUp := mgl32.Vec3{0, 1, 0}
-
-	for !window.ShouldClose() {
-		currentFrame := time.Now()
-		deltaTime := currentFrame.Sub(lastFrame).Seconds()
-		lastFrame = currentFrame
-		timeOfDay += deltaTime * 0.1
-		if timeOfDay > 2*math.Pi {
-			timeOfDay -= 2 * math.Pi
-		}
-
-		// Обработка клавиш и физики
-		playerObj.ProcessKeyboard(window, deltaTime, worldObj)
-
-		playerObj.InteractWithBlock(window, worldObj)
-		// Обновляем мир (генерация / удаление чанков)
-
-		// Освобождаем буферы из VRAM
-		garbageCollector.VramGC(vramGCCh, &render.Cunt_ch)
-
-		// Рендер теней, отражений и сцены
-		dynamicLightPos := render.GetDynamicLightPos(playerObj.Position, timeOfDay)
-		lightProjection := render.GetLightProjection(config)
-		lightView := mgl32.LookAtV(dynamicLightPos, playerObj.Position, lightUp)
-		lightSpaceMatrix := lightProjection.Mul4(lightView)
-		render.RenderDepthMap(depthProgram, worldObj, lightSpaceMatrix, config)
-		// render.RenderReflection(renderProgram, config, worldObj, playerObj, lightSpaceMatrix, dynamicLightPos)
-		render.RenderScene(window, renderProgram, config, worldObj, playerObj, lightSpaceMatrix, dynamicLightPos, deltaTime, textProgram)
-		if playerObj.ShowHUD {
-			render.RenderCrosshair(window, crosshairProgram)
-			if playerObj.ShowInfoPanel {
-				render.RenderDebugHUD(window, textProgram, render.Get_hud_info(deltaTime, worldObj, playerObj))
-			}
-		}
-		window.SwapBuffers()
-		glfw.PollEvents()
-	}
-}
+package mainloop
+
+import (
+	"engine/src/assets"
+	"engine/src/config"
+	"engine/src/console"
+	"engine/src/garbageCollector"
+	"engine/src/input"
+	"engine/src/menu"
+	"engine/src/player"
+	"engine/src/profiler"
+	"engine/src/render"
+	"engine/src/world"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func RunMainLoop(
+	window *glfw.Window,
+	renderProgram, depthProgram, textProgram, crosshairProgram *render.ShaderProgram,
+	config *config.Config,
+	worldObj *world.World,
+	playerObj *player.Camera,
+	vramGCCh chan render.ChunkAllocation,
+) {
+	lastFrame := time.Now()
+
+	// Каналы для асинхронной генерации/удаления чанков
+
+	// Слой действий: клавиатура, мышь и геймпады
+	controller := input.NewController(window, config)
+	pauseMenu := menu.NewPauseMenu()
+	commandLine := console.Attach(window)
+
+	for !window.ShouldClose() {
+		profiler.BeginFrame()
+		currentFrame := time.Now()
+		deltaTime := currentFrame.Sub(lastFrame).Seconds()
+		lastFrame = currentFrame
+		worldObj.Clock.Advance(deltaTime)
+
+		// Изменённые на диске шейдеры, текстуры и описания блоков
+		profiler.Begin("update")
+		assets.Poll()
+
+		// Обработка ввода и физики
+		in := controller.Update(window, deltaTime)
+		if commandLine.Open {
+			// Пока набирается команда, клавиши не управляют игроком; Esc закрывает строку, Enter выполняет
+			if in.Pressed[input.ActionPause] {
+				commandLine.SetOpen(false)
+			} else if in.Pressed[input.ActionMenuSelect] {
+				commandLine.Submit(console.Context{World: worldObj, Player: playerObj, Config: config})
+			}
+			in = input.State{Cursor: in.Cursor}
+			playerObj.ProcessInput(&in, deltaTime, worldObj)
+		} else if in.Pressed[input.ActionPause] {
+			pauseMenu.SetOpen(!pauseMenu.Open, window, controller, playerObj)
+		} else if pauseMenu.Open {
+			if pauseMenu.Update(&in, window, controller, playerObj) {
+				window.SetShouldClose(true)
+			}
+		} else if in.Pressed[input.ActionConsole] {
+			commandLine.SetOpen(true)
+		} else {
+			playerObj.ProcessInput(&in, deltaTime, worldObj)
+			playerObj.InteractWithBlock(&in, worldObj)
+		}
+		playerObj.UpdateView(deltaTime, worldObj)
+		profiler.End()
+		// Обновляем мир (генерация / удаление чанков)
+
+		// Возвращаем в пул участки, освобождённые на прошлом кадре, и загружаем готовые меши
+		profiler.Begin("uploads")
+		garbageCollector.VramGC(vramGCCh, &render.Cunt_ch)
+		render.ProcessChunkUploads(worldObj, playerObj.EyePosition(), vramGCCh, config)
+		profiler.End()
+
+		// Погода у игрока и карта высот для осадков
+		profiler.Begin("weather")
+		worldObj.UpdateWeather(deltaTime, playerObj.Position, config)
+		render.UpdateHeightMap(worldObj, playerObj.EyePosition(), deltaTime)
+		profiler.End()
+
+		// Рендер теней, отражений и сцены
+		dynamicLightPos := render.GetDynamicLightPos(playerObj.Position, worldObj.Clock.Now(), config)
+		sky := render.UpdateSky(dynamicLightPos.Sub(playerObj.Position), config)
+		sky = render.ApplyWeather(sky, worldObj.Weather.Now())
+		cascades := render.ComputeShadowCascades(playerObj, sky.LightDir, config)
+		profiler.Begin("shadow")
+		render.RenderDepthMap(depthProgram, worldObj, cascades, config)
+		profiler.End()
+		profiler.Begin("water")
+		render.RenderReflection(renderProgram, config, worldObj, playerObj, sky)
+		render.RenderRefraction(renderProgram, config, worldObj, playerObj, sky)
+		profiler.End()
+		profiler.Begin("scene")
+		render.RenderScene(window, renderProgram, config, worldObj, playerObj, sky, deltaTime, textProgram)
+		profiler.End()
+		profiler.Begin("hud")
+		if playerObj.ShowHUD {
+			render.RenderCrosshair(window, crosshairProgram)
+			if !playerObj.IsCreative() {
+				render.RenderStatusBars(window, crosshairProgram, playerObj.GetStats())
+			}
+			if playerObj.ShowInfoPanel {
+				render.RenderDebugHUD(window, textProgram, render.Get_hud_info(worldObj, playerObj))
+				render.RenderFrameGraph(window, crosshairProgram)
+			}
+		}
+		render.RenderConsole(window, crosshairProgram, textProgram, commandLine)
+		if pauseMenu.Open {
+			render.RenderPauseMenu(window, crosshairProgram, textProgram, pauseMenu)
+		}
+		profiler.End()
+		profiler.Begin("swap")
+		window.SwapBuffers()
+		profiler.End()
+		glfw.PollEvents()
+		profiler.EndFrame()
+	}
+}
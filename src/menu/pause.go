@@ -0,0 +1,104 @@
+package menu
+
+import (
+	"engine/src/input"
+	"engine/src/player"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Пункты меню паузы
+const (
+	ItemResume = iota
+	ItemQuit
+)
+
+// Размеры пунктов меню в пикселях
+const (
+	ItemWidth   = 260
+	ItemHeight  = 36
+	ItemSpacing = 12
+)
+
+// PauseMenu — меню паузы: останавливает игру и отпускает курсор
+type PauseMenu struct {
+	Open     bool
+	Selected int
+	Items    []string
+
+	lastCursor mgl32.Vec2
+}
+
+func NewPauseMenu() *PauseMenu {
+	return &PauseMenu{
+		Items: []string{"Resume", "Quit"},
+	}
+}
+
+// SetOpen открывает или закрывает меню, переключая захват курсора и физику игрока.
+// После закрытия действия с общими с выбором пункта кнопками молчат, пока их не отпустят:
+// клик по «Resume» не ломает блок, а кнопка A геймпада не прыгает.
+func (m *PauseMenu) SetOpen(open bool, window *glfw.Window, controller *input.Controller, playerObj *player.Camera) {
+	m.Open = open
+	m.Selected = ItemResume
+	if !open {
+		controller.WaitRelease(input.SharingBinding(input.ActionMenuSelect, input.ActionMenuClick)...)
+	}
+	controller.Mouse.SetCaptured(window, !open)
+	playerObj.SetPaused(open)
+}
+
+// Update обрабатывает навигацию по меню. Возвращает true, если выбран выход из игры.
+func (m *PauseMenu) Update(in *input.State, window *glfw.Window, controller *input.Controller, playerObj *player.Camera) bool {
+	if in.Pressed[input.ActionMenuUp] {
+		m.Selected = (m.Selected + len(m.Items) - 1) % len(m.Items)
+	}
+	if in.Pressed[input.ActionMenuDown] {
+		m.Selected = (m.Selected + 1) % len(m.Items)
+	}
+
+	// Движение курсора над пунктом выделяет его, клик — выбирает
+	width, height := window.GetSize()
+	if in.Cursor != m.lastCursor {
+		if hovered := m.ItemAt(in.Cursor, width, height); hovered >= 0 {
+			m.Selected = hovered
+		}
+		m.lastCursor = in.Cursor
+	}
+
+	selected := in.Pressed[input.ActionMenuSelect]
+	if in.Pressed[input.ActionMenuClick] && m.ItemAt(in.Cursor, width, height) == m.Selected {
+		selected = true
+	}
+	if !selected {
+		return false
+	}
+	switch m.Selected {
+	case ItemResume:
+		m.SetOpen(false, window, controller, playerObj)
+	case ItemQuit:
+		return true
+	}
+	return false
+}
+
+// ItemRect возвращает прямоугольник пункта меню (x, y, ширина, высота) для окна заданного размера
+func (m *PauseMenu) ItemRect(i, width, height int) [4]float32 {
+	total := len(m.Items)*(ItemHeight+ItemSpacing) - ItemSpacing
+	x := float32(width-ItemWidth) / 2
+	y := float32(height-total)/2 + float32(i*(ItemHeight+ItemSpacing))
+	return [4]float32{x, y, ItemWidth, ItemHeight}
+}
+
+// ItemAt возвращает индекс пункта под курсором или -1
+func (m *PauseMenu) ItemAt(cursor mgl32.Vec2, width, height int) int {
+	for i := range m.Items {
+		r := m.ItemRect(i, width, height)
+		if cursor.X() >= r[0] && cursor.X() <= r[0]+r[2] &&
+			cursor.Y() >= r[1] && cursor.Y() <= r[1]+r[3] {
+			return i
+		}
+	}
+	return -1
+}
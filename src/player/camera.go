@@ -3,14 +3,14 @@ package player
 import (
 	"fmt"
 	"math"
-	"os"
 	"sync"
 	"time"
 
+	"engine/src/config"
+	"engine/src/input"
 	"engine/src/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
@@ -26,35 +26,52 @@ var (
 
 // Camera описывает положение, ориентацию и «физику» игрока
 type Camera struct {
-	Position    mgl32.Vec3
-	Yaw         float64
-	Pitch       float64
-	Speed       float32
-	Sensitivity float64
-	lastX       float64
-	lastY       float64
-	mu          sync.Mutex
+	Config   *config.Config
+	Position mgl32.Vec3
+	Mode     CameraMode
+	// Точка возрождения после смерти
+	SpawnPoint mgl32.Vec3
+	Yaw        float64
+	Pitch      float64
+	Speed      float32
+	mu         sync.Mutex
 
 	velocityY       float32
 	isOnGround      bool
 	creativeMode    bool // если true — режим «креатива» (полёт, нет коллизий)
 	ShowInfoPanel   bool
 	ShowHUD         bool
+	paused          bool // открыто меню паузы — физика не обновляется
+	sprinting       bool
+	swimUp          bool // удерживается прыжок — всплываем, если в жидкости
+	zooming         bool
+	fov             float32    // текущий угол обзора (плавно стремится к целевому)
+	eye             mgl32.Vec3 // точка, из которой смотрит камера
+	stats           Stats
+	spawnSettled    bool // точка появления опущена на поверхность загруженного чанка
+	fallProtected   bool // урон от падения не считается до первого касания земли после появления
 	lastPlaceAction time.Time
+	HeldBlock       world.Block // блок, который ставит игрок (меняется командой block)
 }
 
-func NewCamera(position mgl32.Vec3) *Camera {
+func NewCamera(position mgl32.Vec3, Config *config.Config) *Camera {
 	return &Camera{
+		Config:        Config,
 		Position:      position,
+		SpawnPoint:    position,
+		eye:           position,
+		stats:         newStats(),
+		fallProtected: true,
+		fov:           Config.FOV,
 		Yaw:           -90.0,
 		Pitch:         0.0,
 		Speed:         105.0,
-		Sensitivity:   0.05,
 		velocityY:     0,
 		isOnGround:    false,
 		creativeMode:  false,
 		ShowInfoPanel: false,
 		ShowHUD:       true,
+		HeldBlock:     world.NamedBlocks["dirt"],
 	}
 }
 
@@ -63,44 +80,51 @@ func (cam *Camera) GetViewMatrix() mgl32.Mat4 {
 	cam.mu.Lock()
 	defer cam.mu.Unlock()
 
-	yawRad := float64(mgl32.DegToRad(float32(cam.Yaw)))
-	pitchRad := float64(mgl32.DegToRad(float32(cam.Pitch)))
-
-	direction := mgl32.Vec3{
-		float32(math.Cos(yawRad) * math.Cos(pitchRad)),
-		float32(math.Sin(pitchRad)),
-		float32(math.Sin(yawRad) * math.Cos(pitchRad)),
-	}.Normalize()
-
-	return mgl32.LookAtV(cam.Position, cam.Position.Add(direction), mgl32.Vec3{0, 1, 0})
+	return mgl32.LookAtV(cam.eye, cam.eye.Add(cam.front()), mgl32.Vec3{0, 1, 0})
 }
 
-// ProcessKeyboard обрабатывает перемещения и переключения режимов
-func (cam *Camera) ProcessKeyboard(window *glfw.Window, deltaTime float64, w *world.World) {
+// ProcessInput обрабатывает перемещения и переключения режимов по действиям ввода
+func (cam *Camera) ProcessInput(in *input.State, deltaTime float64, w *world.World) {
 	cam.mu.Lock()
 	defer cam.mu.Unlock()
 
-	if window.GetKey(glfw.KeyF1) == glfw.Press {
+	if in.Pressed[input.ActionToggleHUD] {
 		cam.ShowHUD = !cam.ShowHUD
-		time.Sleep(200 * time.Millisecond) // Задержка для предотвращения дребезга
 	}
-	if window.GetKey(glfw.KeyF3) == glfw.Press {
+	if in.Pressed[input.ActionToggleInfoPanel] {
 		cam.ShowInfoPanel = !cam.ShowInfoPanel
-		time.Sleep(200 * time.Millisecond) // Задержка для предотвращения дребезга
 	}
 
-	// Переключение креативного режима по клавише ']'
-	if window.GetKey(glfw.KeyRightBracket) == glfw.Press {
+	// Переключение креативного режима
+	if in.Pressed[input.ActionToggleCreative] {
 		cam.creativeMode = !cam.creativeMode
+		cam.velocityY = 0 // скорость падения не переносится между режимами
 		if cam.creativeMode {
 			fmt.Println("Creative mode ON")
 		} else {
 			fmt.Println("Creative mode OFF")
 		}
-		time.Sleep(200 * time.Millisecond)
 	}
 
+	// Переключение вида от первого/третьего лица
+	if in.Pressed[input.ActionToggleView] {
+		if cam.Mode == FirstPerson {
+			cam.Mode = ThirdPerson
+		} else {
+			cam.Mode = FirstPerson
+		}
+	}
+
+	// Поворот взгляда (мышь и стик геймпада)
+	cam.rotate(float64(in.Look.X()), float64(in.Look.Y()))
+
+	cam.zooming = in.Held[input.ActionZoom]
+	cam.sprinting = in.Held[input.ActionSprint] && in.Move.Y() > 0
+
 	speed := cam.Speed * float32(deltaTime)
+	if cam.sprinting {
+		speed *= cam.Config.SprintMultiplier
+	}
 
 	// Направление (без учёта pitch по Y — движение по плоскости)
 	yawRad := float64(mgl32.DegToRad(float32(cam.Yaw)))
@@ -113,24 +137,24 @@ func (cam *Camera) ProcessKeyboard(window *glfw.Window, deltaTime float64, w *wo
 	// Правый вектор
 	right := forward.Cross(mgl32.Vec3{0, 1, 0}).Normalize()
 
+	// Желаемое направление движения по плоскости XZ (длина — доля полной скорости)
+	moveDir := forward.Mul(in.Move.Y()).Add(right.Mul(in.Move.X()))
+	moveLen := moveDir.Len()
+
 	// === Управление в обычном режиме (не креатив) ===
 	if !cam.creativeMode {
-		// WASD по XZ
-		if window.GetKey(glfw.KeyW) == glfw.Press {
-			cam.Position = cam.tryMove(cam.Position, forward, speed, w)
-		}
-		if window.GetKey(glfw.KeyS) == glfw.Press {
-			cam.Position = cam.tryMove(cam.Position, forward.Mul(-1), speed, w)
+		if cam.submersion(w) > 0.5 {
+			speed *= liquidMoveFactor
 		}
-		if window.GetKey(glfw.KeyA) == glfw.Press {
-			cam.Position = cam.tryMove(cam.Position, right.Mul(-1), speed, w)
-		}
-		if window.GetKey(glfw.KeyD) == glfw.Press {
-			cam.Position = cam.tryMove(cam.Position, right, speed, w)
+		if moveLen > 0 {
+			cam.Position = cam.tryMove(cam.Position, moveDir.Mul(1/moveLen), speed*moveLen, w)
 		}
 
-		// Прыжок (пробел)
-		if window.GetKey(glfw.KeySpace) == glfw.Press && cam.isOnGround {
+		// В жидкости прыжок работает как всплытие (применяется в UpdatePhysics)
+		cam.swimUp = in.Held[input.ActionJump]
+
+		// Прыжок
+		if in.Held[input.ActionJump] && cam.isOnGround {
 			cam.velocityY = float32(jumpSpeed)
 			cam.isOnGround = false
 		}
@@ -138,46 +162,19 @@ func (cam *Camera) ProcessKeyboard(window *glfw.Window, deltaTime float64, w *wo
 	} else {
 		// === Управление в креативном режиме ===
 		// Игнорируем коллизии, можем летать свободно
+		cam.Position = cam.Position.Add(moveDir.Mul(speed))
 
-		// Вектор вперёд с учётом Pitch (чтобы можно было летать вверх при наклоне)
-		pitchRad := float64(mgl32.DegToRad(float32(cam.Pitch)))
-		fullForward := mgl32.Vec3{
-			float32(math.Cos(yawRad) * math.Cos(pitchRad)),
-			float32(0),
-			float32(math.Sin(yawRad) * math.Cos(pitchRad)),
-		}.Normalize()
-
-		// Вектор вправо (с учётом pitch=0, обычно)
-		fullRight := fullForward.Cross(mgl32.Vec3{0, 1, 0}).Normalize()
-
-		if window.GetKey(glfw.KeyW) == glfw.Press {
-			cam.Position = cam.Position.Add(fullForward.Mul(speed))
-		}
-		if window.GetKey(glfw.KeyS) == glfw.Press {
-			cam.Position = cam.Position.Sub(fullForward.Mul(speed))
-		}
-		if window.GetKey(glfw.KeyA) == glfw.Press {
-			cam.Position = cam.Position.Sub(fullRight.Mul(speed))
-		}
-		if window.GetKey(glfw.KeyD) == glfw.Press {
-			cam.Position = cam.Position.Add(fullRight.Mul(speed))
-		}
-		// Подъём/опускание по Y (можно также сделать shift / space)
-		if window.GetKey(glfw.KeySpace) == glfw.Press {
+		// Подъём/опускание по Y
+		if in.Held[input.ActionJump] {
 			cam.Position = cam.Position.Add(mgl32.Vec3{0, speed, 0})
 		}
-		if window.GetKey(glfw.KeyLeftShift) == glfw.Press {
+		if in.Held[input.ActionDescend] {
 			cam.Position = cam.Position.Sub(mgl32.Vec3{0, speed, 0})
 		}
 	}
 
-	// Выход из программы
-	if window.GetKey(glfw.KeyEscape) == glfw.Press {
-		os.Exit(0)
-	}
-
 	// Включение wireframe
-	if window.GetKey(glfw.KeyM) == glfw.Press {
+	if in.Pressed[input.ActionToggleWireframe] {
 		wireframeMode = !wireframeMode
 		if wireframeMode {
 			fmt.Println("Wireframe ON")
@@ -186,7 +183,6 @@ func (cam *Camera) ProcessKeyboard(window *glfw.Window, deltaTime float64, w *wo
 			fmt.Println("Wireframe OFF")
 			gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
 		}
-		time.Sleep(200 * time.Millisecond)
 	}
 }
 
@@ -195,13 +191,19 @@ func (cam *Camera) UpdatePhysics(deltaTime float64, w *world.World) {
 	cam.mu.Lock()
 	defer cam.mu.Unlock()
 
-	// Если креативный режим — игнорируем гравитацию и коллизии
-	if cam.creativeMode {
+	// До загрузки чанка под точкой появления игрок висит на месте, а не падает в пустоту
+	if !cam.settleSpawn(w) {
 		return
 	}
 
-	// Обычный режим: гравитация + коллизии
-	cam.velocityY -= float32(gravity) * float32(deltaTime)
+	// Если креативный режим или пауза — игнорируем гравитацию и коллизии
+	if cam.creativeMode || cam.paused {
+		return
+	}
+
+	// Обычный режим: гравитация + коллизии, в жидкости — плавание
+	g := cam.applyLiquidForces(cam.submersion(w), float32(deltaTime))
+	cam.velocityY -= g * float32(deltaTime)
 
 	oldPos := cam.Position
 	newPos := oldPos
@@ -209,6 +211,9 @@ func (cam *Camera) UpdatePhysics(deltaTime float64, w *world.World) {
 
 	if cam.checkCollision(newPos, w) {
 		if cam.velocityY < 0 {
+			if !cam.isOnGround {
+				cam.applyLanding(-cam.velocityY)
+			}
 			cam.isOnGround = true
 		}
 		cam.velocityY = 0
@@ -217,23 +222,22 @@ func (cam *Camera) UpdatePhysics(deltaTime float64, w *world.World) {
 		oldPos = newPos
 	}
 	cam.Position = oldPos
+
+	// Здоровье, воздух и регенерация (respawn может переставить игрока)
+	cam.updateStats(deltaTime, w)
 }
 
-// ProcessMouse — вращение камеры
-func (cam *Camera) ProcessMouse(xpos, ypos float64) {
+// SetPaused останавливает или возобновляет физику игрока
+func (cam *Camera) SetPaused(paused bool) {
 	cam.mu.Lock()
 	defer cam.mu.Unlock()
+	cam.paused = paused
+}
 
-	xOffset := xpos - cam.lastX
-	yOffset := cam.lastY - ypos
-	cam.lastX = xpos
-	cam.lastY = ypos
-
-	xOffset *= cam.Sensitivity
-	yOffset *= cam.Sensitivity
-
-	cam.Yaw += xOffset
-	cam.Pitch += yOffset
+// rotate поворачивает взгляд на заданные углы в градусах с ограничением pitch
+func (cam *Camera) rotate(yawOffset, pitchOffset float64) {
+	cam.Yaw += yawOffset
+	cam.Pitch += pitchOffset
 
 	if cam.Pitch > 89.0 {
 		cam.Pitch = 89.0
@@ -286,7 +290,7 @@ func (cam *Camera) checkCollision(pos mgl32.Vec3, w *world.World) bool {
 	return false
 }
 
-// isSolidAt — проверяет, твёрдый ли блок (Id != 0)
+// isSolidAt — проверяет, твёрдый ли блок (не воздух и не жидкость)
 func isSolidAt(w *world.World, fx, fy, fz float64) bool {
 	x := int(math.Floor(float64(fx)))
 	y := int(math.Floor(float64(fy)))
@@ -296,11 +300,11 @@ func isSolidAt(w *world.World, fx, fy, fz float64) bool {
 		return false
 	}
 	block := w.GetBlock(x, y, z)
-	return block.Id != 0
+	return world.IsSolid(block.Id)
 }
-func (cam *Camera) InteractWithBlock(window *glfw.Window, w *world.World) {
-	// Проверяем нажатие левой кнопки мыши для удаления блока
-	if window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press {
+func (cam *Camera) InteractWithBlock(in *input.State, w *world.World) {
+	// Проверяем действие «сломать» (левая кнопка мыши / правый курок)
+	if in.Held[input.ActionBreak] {
 		currentTime := time.Now()
 		if currentTime.Sub(cam.lastPlaceAction) < 100*time.Millisecond {
 			// Если прошло меньше 500 мс, блок не ставится
@@ -316,8 +320,8 @@ func (cam *Camera) InteractWithBlock(window *glfw.Window, w *world.World) {
 		}
 	}
 
-	// Проверяем нажатие правой кнопки мыши для добавления блока
-	if window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press {
+	// Проверяем действие «поставить» (правая кнопка мыши / левый курок)
+	if in.Held[input.ActionPlace] {
 		currentTime := time.Now()
 		if currentTime.Sub(cam.lastPlaceAction) < 100*time.Millisecond {
 			// Если прошло меньше 500 мс, блок не ставится
@@ -329,15 +333,12 @@ func (cam *Camera) InteractWithBlock(window *glfw.Window, w *world.World) {
 		// checkPos.Normalize()
 		// println(checkPos.X, checkPos.Y, checkPos.Z)
 		if newBlockPos != nil {
-			// Проверяем, чтобы новый блок не заменял существующий
+			// Проверяем, чтобы новый блок не заменял существующий твёрдый (жидкость заменяется)
 			existingBlock := w.GetBlock(newBlockPos[0], newBlockPos[1], newBlockPos[2])
-			if existingBlock.Id == 0 {
+			if !world.IsSolid(existingBlock.Id) {
 				// Добавляем новый блок
 				fmt.Printf("SetBlock %d %d %d (Normal: %v)\n", newBlockPos[0], newBlockPos[1], newBlockPos[2], normal)
-				w.SetBlock(newBlockPos[0], newBlockPos[1], newBlockPos[2], world.Block{
-					Id:    1,                         // Пример ID блока
-					Color: [3]float32{0.8, 0.6, 0.4}, // Пример цвета
-				})
+				w.SetBlock(newBlockPos[0], newBlockPos[1], newBlockPos[2], cam.HeldBlock)
 				cam.lastPlaceAction = currentTime
 			}
 		}
@@ -359,7 +360,7 @@ func (cam *Camera) raycast(w *world.World, maxDistance float64) (*[3]int, mgl32.
 		x, y, z := int(math.Floor(float64(pos.X()))), int(math.Floor(float64(pos.Y()))), int(math.Floor(float64(pos.Z())))
 
 		block := w.GetBlock(x, y, z)
-		if block.Id != 0 {
+		if world.IsSolid(block.Id) {
 			// Вычисляем направление нормали к грани блока
 			epsilon := float32(0.1)
 			dx := pos.X() - float32(x)
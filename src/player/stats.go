@@ -0,0 +1,128 @@
+package player
+
+import (
+	"fmt"
+	"math"
+
+	"engine/src/world"
+)
+
+var (
+	maxHealth        = float32(20.0) // здоровье в «половинках сердец»
+	maxAir           = float32(10.0) // запас воздуха в секундах
+	safeFallSpeed    = float32(22.0) // скорость приземления без урона (прыжок даёт ~20)
+	fallDamageFactor = float32(0.8)  // урон за каждую единицу скорости сверх безопасной
+	drownDamageRate  = float32(2.0)  // урон в секунду без воздуха
+	airRefillRate    = float32(5.0)  // восстановление воздуха в секунду
+	regenDelay       = 5.0           // секунд без урона до начала регенерации
+	regenRate        = float32(1.0)  // регенерация здоровья в секунду
+)
+
+// Stats — параметры выживания игрока
+type Stats struct {
+	Health    float32
+	MaxHealth float32
+	Air       float32
+	MaxAir    float32
+
+	sinceDamage float64 // секунд с последнего урона
+}
+
+func newStats() Stats {
+	return Stats{
+		Health:    maxHealth,
+		MaxHealth: maxHealth,
+		Air:       maxAir,
+		MaxAir:    maxAir,
+	}
+}
+
+// GetStats возвращает копию параметров выживания для HUD
+func (cam *Camera) GetStats() Stats {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.stats
+}
+
+// IsCreative сообщает, включён ли креативный режим
+func (cam *Camera) IsCreative() bool {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.creativeMode
+}
+
+// damage наносит урон и сбрасывает таймер регенерации
+func (cam *Camera) damage(amount float32, reason string) {
+	if amount <= 0 {
+		return
+	}
+	cam.stats.Health -= amount
+	cam.stats.sinceDamage = 0
+	if cam.stats.Health <= 0 {
+		fmt.Printf("You died: %s\n", reason)
+		cam.respawn()
+	}
+}
+
+// applyLanding считает урон от падения по скорости в момент касания земли.
+// Первое приземление после появления урона не наносит.
+func (cam *Camera) applyLanding(impactSpeed float32) {
+	if cam.fallProtected {
+		cam.fallProtected = false
+		return
+	}
+	if impactSpeed <= safeFallSpeed {
+		return
+	}
+	cam.damage(float32(math.Floor(float64((impactSpeed-safeFallSpeed)*fallDamageFactor))), "fell from a high place")
+}
+
+// updateStats обновляет воздух, утопление и регенерацию
+func (cam *Camera) updateStats(deltaTime float64, w *world.World) {
+	dt := float32(deltaTime)
+	cam.stats.sinceDamage += deltaTime
+
+	if w.IsLiquidAt(cam.Position.X(), cam.Position.Y(), cam.Position.Z()) {
+		cam.stats.Air -= dt
+		if cam.stats.Air <= 0 {
+			cam.stats.Air = 0
+			cam.damage(drownDamageRate*dt, "drowned")
+		}
+	} else if cam.stats.Air < cam.stats.MaxAir {
+		cam.stats.Air = float32(math.Min(float64(cam.stats.Air+airRefillRate*dt), float64(cam.stats.MaxAir)))
+	}
+
+	if cam.stats.sinceDamage >= regenDelay && cam.stats.Health < cam.stats.MaxHealth {
+		cam.stats.Health = float32(math.Min(float64(cam.stats.Health+regenRate*dt), float64(cam.stats.MaxHealth)))
+	}
+}
+
+// respawn возвращает игрока в точку появления с полным здоровьем
+func (cam *Camera) respawn() {
+	cam.Position = cam.SpawnPoint
+	cam.velocityY = 0
+	cam.isOnGround = false
+	cam.fallProtected = true
+	cam.stats = newStats()
+}
+
+// settleSpawn ставит точку появления на поверхность, как только загрузится чанк под ней:
+// стартовая высота задаётся с запасом и обычно висит в воздухе. Игрок, ещё стоящий в точке
+// появления, переносится вместе с ней. Возвращает false, пока он ждёт загрузки чанка.
+func (cam *Camera) settleSpawn(w *world.World) bool {
+	if cam.spawnSettled {
+		return true
+	}
+	atSpawn := cam.Position == cam.SpawnPoint
+	height, ok := w.HeightAt(int(math.Floor(float64(cam.SpawnPoint.X()))), int(math.Floor(float64(cam.SpawnPoint.Z()))))
+	if !ok {
+		return !atSpawn
+	}
+	cam.SpawnPoint[1] = float32(height) + float32(playerEyeOffset)
+	if atSpawn {
+		cam.Position = cam.SpawnPoint
+		cam.velocityY = 0
+	}
+	cam.spawnSettled = true
+	return true
+}
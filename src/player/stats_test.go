@@ -0,0 +1,56 @@
+package player
+
+import (
+	"engine/src/config"
+	"engine/src/world"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Свежий спавн в режиме выживания: точка появления задана с запасом над рельефом (как в
+// main.go), чанк под ней загружается не сразу. Игрок должен дождаться чанка, встать на
+// поверхность и не получить урона.
+func TestSurvivalSpawnLandsWithoutDamage(t *testing.T) {
+	Config := config.DefaultConfig()
+	Config.MaxTerrainHeight = 0.6
+	Config.SeaLevel = 0.15
+	Config.WarpScale = 94
+	Config.WarpAmp = 60
+
+	w := world.NewWorld(16, 64, 16)
+	spawn := mgl32.Vec3{0.5, 120, 0.5}
+	cam := NewCamera(spawn, &Config)
+
+	const dt = 1.0 / 64
+	for i := 0; i < 32; i++ {
+		cam.UpdatePhysics(dt, w)
+	}
+	if cam.Position != spawn {
+		t.Fatalf("player moved to %v before the spawn chunk was loaded", cam.Position)
+	}
+
+	w.GenerateChunk(0, 0, &Config)
+	height, ok := w.HeightAt(0, 0)
+	if !ok {
+		t.Fatal("spawn chunk was not generated")
+	}
+	for i := 0; i < 3*64; i++ {
+		cam.UpdatePhysics(dt, w)
+	}
+
+	stats := cam.GetStats()
+	if stats.Health != stats.MaxHealth {
+		t.Errorf("health after spawning = %v, want %v", stats.Health, stats.MaxHealth)
+	}
+	if !cam.isOnGround {
+		t.Errorf("player at %v is not on the ground", cam.Position)
+	}
+	want := float32(height) + float32(playerEyeOffset)
+	if d := cam.Position.Y() - want; d < -0.1 || d > 0.1 {
+		t.Errorf("player height = %v, want about %v (surface %d)", cam.Position.Y(), want, height)
+	}
+	if cam.SpawnPoint.Y() != want {
+		t.Errorf("SpawnPoint.Y = %v, want %v", cam.SpawnPoint.Y(), want)
+	}
+}
@@ -0,0 +1,54 @@
+package player
+
+import (
+	"math"
+
+	"engine/src/world"
+)
+
+var (
+	liquidGravityFactor = float32(0.3) // доля гравитации при полном погружении
+	buoyancy            = float32(8.0) // выталкивающее ускорение при полном погружении
+	liquidDrag          = float32(3.0) // вязкость жидкости (затухание вертикальной скорости)
+	swimAccel           = float32(30.0)
+	swimUpSpeed         = float32(5.0) // максимальная скорость всплытия
+	maxSinkSpeed        = float32(6.0) // предельная скорость погружения
+	liquidMoveFactor    = float32(0.5) // замедление ходьбы в жидкости
+)
+
+// Высоты (от ступней) точек, по которым оценивается погружение тела
+var submersionSamples = []float32{0.1, 0.5, 0.9, 1.3, 1.7}
+
+// submersion возвращает долю тела игрока, находящуюся в жидкости (0 — на суше, 1 — целиком)
+func (cam *Camera) submersion(w *world.World) float32 {
+	feetY := cam.Position.Y() - float32(playerEyeOffset)
+	inLiquid := 0
+	for _, h := range submersionSamples {
+		if w.IsLiquidAt(cam.Position.X(), feetY+h, cam.Position.Z()) {
+			inLiquid++
+		}
+	}
+	return float32(inLiquid) / float32(len(submersionSamples))
+}
+
+// applyLiquidForces меняет вертикальную скорость в жидкости: уменьшенная гравитация,
+// выталкивание, вязкое сопротивление и всплытие по прыжку. Возвращает эффективную гравитацию.
+func (cam *Camera) applyLiquidForces(sub float32, dt float32) float32 {
+	if sub <= 0 {
+		return float32(gravity)
+	}
+	g := float32(gravity) * (1 - (1-liquidGravityFactor)*sub)
+	cam.velocityY += buoyancy * sub * dt
+	cam.velocityY *= float32(math.Exp(float64(-liquidDrag * sub * dt)))
+
+	if cam.swimUp && cam.velocityY < swimUpSpeed {
+		cam.velocityY += swimAccel * dt
+		if cam.velocityY > swimUpSpeed {
+			cam.velocityY = swimUpSpeed
+		}
+	}
+	if cam.velocityY < -maxSinkSpeed {
+		cam.velocityY = -maxSinkSpeed
+	}
+	return g
+}
@@ -0,0 +1,111 @@
+package player
+
+import (
+	"math"
+
+	"engine/src/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CameraMode — режим обзора
+type CameraMode int
+
+const (
+	FirstPerson CameraMode = iota
+	ThirdPerson
+)
+
+// Зазор между камерой от третьего лица и препятствием
+const boomMargin = 0.2
+
+// Front возвращает единичный вектор направления взгляда
+func (cam *Camera) Front() mgl32.Vec3 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.front()
+}
+
+func (cam *Camera) front() mgl32.Vec3 {
+	yawRad := float64(mgl32.DegToRad(float32(cam.Yaw)))
+	pitchRad := float64(mgl32.DegToRad(float32(cam.Pitch)))
+
+	return mgl32.Vec3{
+		float32(math.Cos(yawRad) * math.Cos(pitchRad)),
+		float32(math.Sin(pitchRad)),
+		float32(math.Sin(yawRad) * math.Cos(pitchRad)),
+	}.Normalize()
+}
+
+// UpdateView пересчитывает положение глаза и угол обзора за кадр:
+// плавно меняет FOV (спринт, зум) и укорачивает штангу камеры от третьего лица,
+// если между игроком и камерой есть блоки.
+func (cam *Camera) UpdateView(deltaTime float64, w *world.World) {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+
+	targetFOV := cam.Config.FOV
+	if cam.sprinting {
+		targetFOV += cam.Config.SprintFOVBoost
+	}
+	if cam.zooming {
+		targetFOV = cam.Config.ZoomFOV
+	}
+	k := float32(math.Min(1, deltaTime*10))
+	cam.fov += (targetFOV - cam.fov) * k
+
+	if cam.Mode != ThirdPerson {
+		cam.eye = cam.Position
+		return
+	}
+
+	back := cam.front().Mul(-1)
+	dist := cam.Config.ThirdPersonDistance
+	if hit, ok := w.Raycast(cam.Position, back, dist, nil); ok {
+		dist = hit.Distance - boomMargin
+		if dist < 0 {
+			dist = 0
+		}
+	}
+	cam.eye = cam.Position.Add(back.Mul(dist))
+}
+
+// EyePosition возвращает точку, из которой смотрит камера
+func (cam *Camera) EyePosition() mgl32.Vec3 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.eye
+}
+
+// FeetPosition возвращает точку между ступнями игрока
+func (cam *Camera) FeetPosition() mgl32.Vec3 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.Position.Sub(mgl32.Vec3{0, float32(playerEyeOffset), 0})
+}
+
+// GetProjectionMatrix возвращает матрицу перспективной проекции с текущим FOV
+func (cam *Camera) GetProjectionMatrix(aspect float32) mgl32.Mat4 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return mgl32.Perspective(mgl32.DegToRad(cam.fov), aspect, cam.Config.NearPlane, cam.Config.FarPlane)
+}
+
+// FOV возвращает текущий вертикальный угол обзора в градусах
+func (cam *Camera) FOV() float32 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+	return cam.fov
+}
+
+// GetReflectionViewMatrix возвращает матрицу вида камеры, отражённой относительно плоскости y = planeY
+func (cam *Camera) GetReflectionViewMatrix(planeY float32) mgl32.Mat4 {
+	cam.mu.Lock()
+	defer cam.mu.Unlock()
+
+	eye := cam.eye
+	eye[1] = 2*planeY - eye[1]
+	dir := cam.front()
+	dir[1] = -dir[1]
+	return mgl32.LookAtV(eye, eye.Add(dir), mgl32.Vec3{0, 1, 0})
+}
@@ -0,0 +1,140 @@
+package profiler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dump записывает историю кадров в файл: *.json — трасса в формате Chrome Trace Event
+// (chrome://tracing, Perfetto), иначе CSV — строка на кадр, столбцы областей и счётчиков.
+func Dump(path string) error {
+	frames := Frames()
+	if len(frames) == 0 {
+		return fmt.Errorf("no frames recorded")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = writeTrace(out, frames)
+	} else {
+		err = writeCSV(out, frames)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeCSV — кадр, время, вызовы отрисовки, треугольники, CPU/GPU каждой области и счётчики.
+// Пустая ячейка — область в кадре не выполнялась или результат GPU не получен.
+func writeCSV(out *bufio.Writer, frames []*Frame) error {
+	var scopes, counters []string
+	seenScope, seenCounter := map[string]bool{}, map[string]bool{}
+	for _, frame := range frames {
+		for _, scope := range frame.Scopes {
+			if !seenScope[scope.Name] {
+				seenScope[scope.Name] = true
+				scopes = append(scopes, scope.Name)
+			}
+		}
+		for _, counter := range frame.Counters {
+			if !seenCounter[counter.Name] {
+				seenCounter[counter.Name] = true
+				counters = append(counters, counter.Name)
+			}
+		}
+	}
+
+	header := []string{"frame", "frame_ms", "draw_calls", "triangles"}
+	for _, name := range scopes {
+		header = append(header, name+"_cpu_ms", name+"_gpu_ms")
+	}
+	header = append(header, counters...)
+	fmt.Fprintln(out, strings.Join(header, ","))
+
+	for _, frame := range frames {
+		row := []string{
+			fmt.Sprint(frame.Index),
+			fmt.Sprintf("%.3f", frame.Millis),
+			fmt.Sprint(frame.DrawCalls),
+			fmt.Sprint(frame.Triangles),
+		}
+		for _, name := range scopes {
+			cpu, gpu := "", ""
+			for _, scope := range frame.Scopes {
+				if scope.Name != name {
+					continue
+				}
+				cpu = fmt.Sprintf("%.3f", scope.CPU)
+				if scope.GPU >= 0 {
+					gpu = fmt.Sprintf("%.3f", scope.GPU)
+				}
+				break
+			}
+			row = append(row, cpu, gpu)
+		}
+		for _, name := range counters {
+			value := ""
+			for _, counter := range frame.Counters {
+				if counter.Name == name {
+					value = fmt.Sprint(counter.Value)
+					break
+				}
+			}
+			row = append(row, value)
+		}
+		if _, err := fmt.Fprintln(out, strings.Join(row, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceEvent — событие трассы Chrome: ph "X" — отрезок, "C" — значение счётчика
+type traceEvent struct {
+	Name  string         `json:"name"`
+	Phase string         `json:"ph"`
+	Time  float64        `json:"ts"` // мкс
+	Dur   float64        `json:"dur"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+// writeTrace — области CPU в потоке 1, GPU — в потоке 2. Отрезок GPU ставится на начало
+// области по процессору: сами метки GL идут по другим часам.
+func writeTrace(out *bufio.Writer, frames []*Frame) error {
+	origin := frames[0].Start
+	var events []traceEvent
+	for _, frame := range frames {
+		start := float64(frame.Start.Sub(origin).Microseconds())
+		events = append(events, traceEvent{
+			Name: "frame", Phase: "X", Time: start, Dur: frame.Millis * 1000, PID: 1, TID: 1,
+			Args: map[string]any{"index": frame.Index, "draw_calls": frame.DrawCalls, "triangles": frame.Triangles},
+		})
+		for _, scope := range frame.Scopes {
+			ts := start + scope.Start*1000
+			events = append(events, traceEvent{Name: scope.Name, Phase: "X", Time: ts, Dur: scope.CPU * 1000, PID: 1, TID: 1})
+			if scope.GPU >= 0 {
+				events = append(events, traceEvent{Name: scope.Name, Phase: "X", Time: ts, Dur: scope.GPU * 1000, PID: 1, TID: 2})
+			}
+		}
+		for _, counter := range frame.Counters {
+			events = append(events, traceEvent{
+				Name: counter.Name, Phase: "C", Time: start, PID: 1,
+				Args: map[string]any{"value": counter.Value},
+			})
+		}
+	}
+	return json.NewEncoder(out).Encode(map[string]any{"traceEvents": events, "displayTimeUnit": "ms"})
+}
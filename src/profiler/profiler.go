@@ -0,0 +1,295 @@
+package profiler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Профилировщик кадра: вложенные области Begin/End замеряются по часам процессора и,
+// после Init, метками времени GL (glQueryCounter). Результаты GPU приходят через несколько
+// кадров и дописываются в уже сохранённый кадр. Кроме времени кадр хранит счётчики:
+// вызовы отрисовки, треугольники и значения SetCounter. Всё вызывается из GL-потока.
+
+// Сколько последних кадров хранится для графика, процентилей и выгрузки
+const historySize = 600
+
+// Окно (в кадрах) для средних по областям на панели
+const summaryFrames = 120
+
+// ScopeTiming — замер одной области кадра
+type ScopeTiming struct {
+	Name  string
+	Depth int     // вложенность: 0 — область верхнего уровня
+	Start float64 // начало от начала кадра, мс
+	CPU   float64 // мс
+	GPU   float64 // мс, -1 — результата ещё нет или таймеры GL выключены
+
+	queries [2]uint32 // метки времени начала и конца
+}
+
+// Counter — именованное значение, снятое за кадр
+type Counter struct {
+	Name  string
+	Value int
+}
+
+// Frame — замеры одного кадра
+type Frame struct {
+	Index     uint64
+	Start     time.Time
+	Millis    float64 // длительность кадра по процессору
+	Scopes    []ScopeTiming
+	DrawCalls int
+	Triangles int
+	Counters  []Counter
+
+	pending int // областей, ждущих результат GPU
+}
+
+var (
+	history    [historySize]*Frame // кольцо завершённых кадров
+	frameCount uint64
+
+	current    *Frame
+	stack      []int       // открытые области текущего кадра
+	stackStart []time.Time // их начало по часам процессора
+
+	gpuTimers     bool
+	freeQueries   []uint32
+	pendingFrames []*Frame // кадры с незабранными результатами GPU, старые первыми
+)
+
+// Init включает таймеры GL. Вызывается после создания контекста
+func Init() {
+	gpuTimers = true
+}
+
+// BeginFrame начинает замеры кадра и забирает готовые результаты GPU прошлых кадров
+func BeginFrame() {
+	collectGPUResults()
+	current = &Frame{Index: frameCount, Start: time.Now()}
+	frameCount++
+	stack = stack[:0]
+	stackStart = stackStart[:0]
+}
+
+// EndFrame закрывает кадр и кладёт его в историю
+func EndFrame() {
+	if current == nil {
+		return
+	}
+	for len(stack) > 0 {
+		End()
+	}
+	current.Millis = millisSince(current.Start)
+	history[current.Index%historySize] = current
+	if current.pending > 0 {
+		pendingFrames = append(pendingFrames, current)
+	}
+	current = nil
+}
+
+// Begin открывает область name внутри текущей
+func Begin(name string) {
+	if current == nil {
+		return
+	}
+	now := time.Now()
+	scope := ScopeTiming{
+		Name:  name,
+		Depth: len(stack),
+		Start: float64(now.Sub(current.Start).Microseconds()) / 1000,
+		GPU:   -1,
+	}
+	if gpuTimers {
+		scope.queries = [2]uint32{allocQuery(), allocQuery()}
+		gl.QueryCounter(scope.queries[0], gl.TIMESTAMP)
+	}
+	current.Scopes = append(current.Scopes, scope)
+	stack = append(stack, len(current.Scopes)-1)
+	stackStart = append(stackStart, now)
+}
+
+// End закрывает последнюю открытую область
+func End() {
+	if current == nil || len(stack) == 0 {
+		return
+	}
+	last := len(stack) - 1
+	scope := &current.Scopes[stack[last]]
+	scope.CPU = millisSince(stackStart[last])
+	if scope.queries[1] != 0 {
+		gl.QueryCounter(scope.queries[1], gl.TIMESTAMP)
+		current.pending++
+	}
+	stack = stack[:last]
+	stackStart = stackStart[:last]
+}
+
+// Measure открывает область и возвращает функцию, закрывающую её: defer profiler.Measure("x")()
+func Measure(name string) func() {
+	Begin(name)
+	return End
+}
+
+// CountDraw учитывает вызов отрисовки с triangles треугольниками
+func CountDraw(triangles int) {
+	if current == nil {
+		return
+	}
+	current.DrawCalls++
+	current.Triangles += triangles
+}
+
+// SetCounter задаёт значение счётчика name в текущем кадре
+func SetCounter(name string, value int) {
+	if current == nil {
+		return
+	}
+	for i := range current.Counters {
+		if current.Counters[i].Name == name {
+			current.Counters[i].Value = value
+			return
+		}
+	}
+	current.Counters = append(current.Counters, Counter{Name: name, Value: value})
+}
+
+func millisSince(t time.Time) float64 {
+	return float64(time.Since(t).Microseconds()) / 1000
+}
+
+func allocQuery() uint32 {
+	if n := len(freeQueries); n > 0 {
+		id := freeQueries[n-1]
+		freeQueries = freeQueries[:n-1]
+		return id
+	}
+	var id uint32
+	gl.GenQueries(1, &id)
+	return id
+}
+
+// collectGPUResults переносит готовые метки времени в кадры. Запросы завершаются
+// по порядку, поэтому на первом неготовом обход останавливается, не дожидаясь GPU.
+func collectGPUResults() {
+	done := 0
+	for _, frame := range pendingFrames {
+		for i := range frame.Scopes {
+			scope := &frame.Scopes[i]
+			if scope.queries[1] == 0 || scope.GPU >= 0 {
+				continue
+			}
+			var available int32
+			gl.GetQueryObjectiv(scope.queries[1], gl.QUERY_RESULT_AVAILABLE, &available)
+			if available == 0 {
+				pendingFrames = pendingFrames[done:]
+				return
+			}
+			var start, end uint64
+			gl.GetQueryObjectui64v(scope.queries[0], gl.QUERY_RESULT, &start)
+			gl.GetQueryObjectui64v(scope.queries[1], gl.QUERY_RESULT, &end)
+			scope.GPU = float64(end-start) / 1e6
+			freeQueries = append(freeQueries, scope.queries[0], scope.queries[1])
+			scope.queries = [2]uint32{}
+			frame.pending--
+		}
+		done++
+	}
+	pendingFrames = pendingFrames[:0]
+}
+
+// Frames возвращает сохранённые кадры от старых к новым
+func Frames() []*Frame {
+	frames := make([]*Frame, 0, historySize)
+	first := uint64(0)
+	if frameCount > historySize {
+		first = frameCount - historySize
+	}
+	for i := first; i < frameCount; i++ {
+		if frame := history[i%historySize]; frame != nil && frame.Index == i {
+			frames = append(frames, frame)
+		}
+	}
+	return frames
+}
+
+// ScopeSummary — средние времена области за окно summaryFrames
+type ScopeSummary struct {
+	Name  string
+	Depth int
+	CPU   float64
+	GPU   float64 // -1, если результатов GPU нет
+}
+
+// Summary — сводка по истории кадров для панели
+type Summary struct {
+	Frames                        int
+	AvgMillis, P50, P95, P99, Max float64
+	DrawCalls, Triangles          int // последнего кадра
+	Counters                      []Counter
+	Scopes                        []ScopeSummary // в порядке первого появления
+}
+
+// Summarize считает процентили времени кадра по всей истории и средние областей за последние кадры
+func Summarize() Summary {
+	frames := Frames()
+	s := Summary{Frames: len(frames)}
+	if len(frames) == 0 {
+		return s
+	}
+	times := make([]float64, len(frames))
+	total := 0.0
+	for i, frame := range frames {
+		times[i] = frame.Millis
+		total += frame.Millis
+	}
+	sort.Float64s(times)
+	s.AvgMillis = total / float64(len(times))
+	s.P50, s.P95, s.P99 = percentile(times, 0.50), percentile(times, 0.95), percentile(times, 0.99)
+	s.Max = times[len(times)-1]
+
+	last := frames[len(frames)-1]
+	s.DrawCalls, s.Triangles, s.Counters = last.DrawCalls, last.Triangles, last.Counters
+
+	type sums struct {
+		cpu, gpu   float64
+		cpuN, gpuN int
+		depth      int
+	}
+	byName := map[string]*sums{}
+	var names []string
+	for _, frame := range frames[max(0, len(frames)-summaryFrames):] {
+		for _, scope := range frame.Scopes {
+			acc, ok := byName[scope.Name]
+			if !ok {
+				acc = &sums{depth: scope.Depth}
+				byName[scope.Name] = acc
+				names = append(names, scope.Name)
+			}
+			acc.cpu += scope.CPU
+			acc.cpuN++
+			if scope.GPU >= 0 {
+				acc.gpu += scope.GPU
+				acc.gpuN++
+			}
+		}
+	}
+	for _, name := range names {
+		acc := byName[name]
+		summary := ScopeSummary{Name: name, Depth: acc.depth, CPU: acc.cpu / float64(acc.cpuN), GPU: -1}
+		if acc.gpuN > 0 {
+			summary.GPU = acc.gpu / float64(acc.gpuN)
+		}
+		s.Scopes = append(s.Scopes, summary)
+	}
+	return s
+}
+
+// percentile — значение отсортированного ряда на доле p (ближайший ранг)
+func percentile(sorted []float64, p float64) float64 {
+	i := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ArenaRange — участок общего буфера в элементах арены (вершинах или индексах)
+type ArenaRange struct {
+	Offset int
+	Size   int
+}
+
+// bufferArena — большой GL-буфер, из которого чанкам выделяются участки.
+// Свободные участки хранятся отсортированными по смещению и сливаются при освобождении.
+type bufferArena struct {
+	Target   uint32 // gl.ARRAY_BUFFER или gl.ELEMENT_ARRAY_BUFFER
+	Unit     int    // размер элемента в байтах
+	Buffer   uint32
+	Capacity int // в элементах
+	Used     int
+
+	free []ArenaRange
+}
+
+func newBufferArena(target uint32, unit, capacity int) *bufferArena {
+	a := &bufferArena{Target: target, Unit: unit}
+	gl.GenBuffers(1, &a.Buffer)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, a.Buffer)
+	gl.BufferData(gl.COPY_WRITE_BUFFER, capacity*unit, nil, gl.DYNAMIC_DRAW)
+	a.Capacity = capacity
+	a.free = []ArenaRange{{Offset: 0, Size: capacity}}
+	return a
+}
+
+// Alloc выделяет участок из size элементов (first-fit). Если места нет, арена растёт,
+// и grew == true: вызывающий должен перепривязать буфер к VAO.
+func (a *bufferArena) Alloc(size int) (r ArenaRange, grew bool) {
+	for {
+		for i, f := range a.free {
+			if f.Size < size {
+				continue
+			}
+			r = ArenaRange{Offset: f.Offset, Size: size}
+			if f.Size == size {
+				a.free = append(a.free[:i], a.free[i+1:]...)
+			} else {
+				a.free[i] = ArenaRange{Offset: f.Offset + size, Size: f.Size - size}
+			}
+			a.Used += size
+			return r, grew
+		}
+		a.grow(size)
+		grew = true
+	}
+}
+
+// Free возвращает участок в пул
+func (a *bufferArena) Free(r ArenaRange) {
+	if r.Size == 0 {
+		return
+	}
+	a.Used -= r.Size
+	a.insertFree(r)
+}
+
+// insertFree добавляет свободный участок и сливает его с соседними
+func (a *bufferArena) insertFree(r ArenaRange) {
+	i := sort.Search(len(a.free), func(i int) bool { return a.free[i].Offset > r.Offset })
+	a.free = append(a.free, ArenaRange{})
+	copy(a.free[i+1:], a.free[i:])
+	a.free[i] = r
+
+	if i+1 < len(a.free) && a.free[i].Offset+a.free[i].Size == a.free[i+1].Offset {
+		a.free[i].Size += a.free[i+1].Size
+		a.free = append(a.free[:i+1], a.free[i+2:]...)
+	}
+	if i > 0 && a.free[i-1].Offset+a.free[i-1].Size == a.free[i].Offset {
+		a.free[i-1].Size += a.free[i].Size
+		a.free = append(a.free[:i], a.free[i+1:]...)
+	}
+}
+
+// Upload записывает данные в выделенный участок (BufferSubData, без осиротения буфера)
+func (a *bufferArena) Upload(r ArenaRange, data any, bytes int) {
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, a.Buffer)
+	gl.BufferSubData(gl.COPY_WRITE_BUFFER, r.Offset*a.Unit, bytes, gl.Ptr(data))
+}
+
+// grow как минимум удваивает буфер и копирует в новый старое содержимое
+func (a *bufferArena) grow(need int) {
+	newCapacity := a.Capacity * 2
+	for newCapacity-a.Capacity < need {
+		newCapacity *= 2
+	}
+
+	var buffer uint32
+	gl.GenBuffers(1, &buffer)
+	gl.BindBuffer(gl.COPY_WRITE_BUFFER, buffer)
+	gl.BufferData(gl.COPY_WRITE_BUFFER, newCapacity*a.Unit, nil, gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.COPY_READ_BUFFER, a.Buffer)
+	gl.CopyBufferSubData(gl.COPY_READ_BUFFER, gl.COPY_WRITE_BUFFER, 0, 0, a.Capacity*a.Unit)
+	gl.DeleteBuffers(1, &a.Buffer)
+
+	a.Buffer = buffer
+	a.insertFree(ArenaRange{Offset: a.Capacity, Size: newCapacity - a.Capacity})
+	a.Capacity = newCapacity
+}
@@ -0,0 +1,222 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/profiler"
+	"engine/src/world"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// gpuChunk — участки чанка в общих буферах. Создаются, читаются и освобождаются только в GL-потоке
+type gpuChunk struct {
+	ChunkID      uint64
+	Version      uint64
+	Alloc        ChunkAllocation
+	IndicesCount int32
+	Bounds       [2]mgl32.Vec3
+	LOD          int                       // уровень детализации загруженного меша
+	Sections     []world.SectionVisibility // граф видимости секций для отсечения пещер
+	occluded     bool                      // скрыт рельефом от камеры игрока в этом кадре
+	Lights       []world.PointLight        // светящиеся блоки чанка
+}
+
+// ChunkAllocation — участки вершин и индексов чанка, возвращаемые в пул через VramGC.
+// Unloaded отличает выгрузку чанка от замены меша при перестройке.
+type ChunkAllocation struct {
+	Vertices ArenaRange
+	Indices  ArenaRange
+	Unloaded bool
+}
+
+// Общие буферы всех чанков и VAO, описывающий формат их вершин
+var (
+	chunkVAO         uint32
+	chunkVertexArena *bufferArena
+	chunkIndexArena  *bufferArena
+)
+
+const (
+	chunkVertexStride      = world.VertexFloats * 4
+	chunkArenaInitVertices = 1 << 20 // начальная ёмкость арен, дальше растут удвоением
+	chunkArenaInitIndices  = 3 << 20
+)
+
+// Загруженные в видеопамять чанки по координатам
+var gpuChunks = make(map[[2]int]*gpuChunk)
+
+// Меши, ожидающие загрузки: по координате хранится только самый свежий
+var pendingUploads = make(map[[2]int]*world.ChunkMesh)
+
+// uploadStats — статистика очереди загрузки для отладочной панели
+type uploadStats struct {
+	Queued   int     // мешей в очереди после кадра
+	Uploaded int     // загружено за последний кадр
+	Bytes    int     // байт загружено за последний кадр
+	Millis   float64 // время загрузки за последний кадр
+}
+
+var lastUploadStats uploadStats
+
+// ProcessChunkUploads забирает готовые меши из worldObj.MeshCh и загружает их в видеопамять,
+// ближайшие к камере первыми, пока не исчерпан бюджет кадра (UploadBudgetKB / UploadBudgetMs).
+// Буферы выгруженных чанков отправляются в vramGCCh. Вызывается из GL-потока раз в кадр.
+func ProcessChunkUploads(worldObj *world.World, cameraPos mgl32.Vec3, vramGCCh chan ChunkAllocation, Config *config.Config) {
+	for drained := false; !drained; {
+		select {
+		case mesh := <-worldObj.MeshCh:
+			if mesh.Unload {
+				// Выгрузку применяем сразу, заодно выбрасываем ожидающий меш этого чанка
+				if pending, ok := pendingUploads[mesh.Coord]; ok && pending.ChunkID == mesh.ChunkID {
+					delete(pendingUploads, mesh.Coord)
+				}
+				applyChunkMesh(mesh, vramGCCh)
+			} else if pending, ok := pendingUploads[mesh.Coord]; !ok || pending.ChunkID != mesh.ChunkID || pending.Version < mesh.Version {
+				pendingUploads[mesh.Coord] = mesh
+			}
+		default:
+			drained = true
+		}
+	}
+
+	stats := uploadStats{}
+	if len(pendingUploads) > 0 {
+		queue := make([]*world.ChunkMesh, 0, len(pendingUploads))
+		for _, mesh := range pendingUploads {
+			queue = append(queue, mesh)
+		}
+		sort.Slice(queue, func(i, j int) bool {
+			return chunkDistanceSq(queue[i], cameraPos) < chunkDistanceSq(queue[j], cameraPos)
+		})
+
+		budgetBytes := Config.UploadBudgetKB * 1024
+		budgetTime := time.Duration(Config.UploadBudgetMs * float64(time.Millisecond))
+		start := time.Now()
+		for _, mesh := range queue {
+			size := (len(mesh.Vertices) + len(mesh.Indices)) * 4
+			// Хотя бы один меш за кадр грузим всегда, иначе крупный меш не пройдёт никогда
+			if stats.Uploaded > 0 && (stats.Bytes+size > budgetBytes || time.Since(start) >= budgetTime) {
+				break
+			}
+			delete(pendingUploads, mesh.Coord)
+			applyChunkMesh(mesh, vramGCCh)
+			stats.Uploaded++
+			stats.Bytes += size
+		}
+		stats.Millis = float64(time.Since(start).Microseconds()) / 1000
+	}
+	stats.Queued = len(pendingUploads)
+	lastUploadStats = stats
+
+	// Конвейер чанков: генерация → мешинг → очередь загрузки → видеопамять
+	queued, _, _ := worldObj.Scheduler.Stats()
+	states := worldObj.ChunkStates()
+	profiler.SetCounter("chunks_queued", queued)
+	profiler.SetCounter("chunks_generating", states[world.ChunkGenerating])
+	profiler.SetCounter("chunks_meshing", states[world.ChunkMeshing])
+	profiler.SetCounter("upload_queue", stats.Queued)
+	profiler.SetCounter("chunks_uploaded", len(gpuChunks))
+}
+
+// chunkDistanceSq — квадрат расстояния по XZ от камеры до центра чанка
+func chunkDistanceSq(mesh *world.ChunkMesh, cameraPos mgl32.Vec3) float32 {
+	center := mesh.Bounds[0].Add(mesh.Bounds[1]).Mul(0.5)
+	dx, dz := center.X()-cameraPos.X(), center.Z()-cameraPos.Z()
+	return dx*dx + dz*dz
+}
+
+func applyChunkMesh(mesh *world.ChunkMesh, vramGCCh chan ChunkAllocation) {
+	gc, exists := gpuChunks[mesh.Coord]
+
+	if mesh.Unload {
+		// Сообщение о выгрузке старого чанка могло прийти после меша нового на той же координате
+		if exists && gc.ChunkID == mesh.ChunkID {
+			releaseChunkBuffers(gc, true, vramGCCh)
+			delete(gpuChunks, mesh.Coord)
+		}
+		return
+	}
+
+	if exists && gc.ChunkID == mesh.ChunkID && gc.Version >= mesh.Version {
+		return // устаревший меш
+	}
+	if exists && gc.ChunkID != mesh.ChunkID {
+		releaseChunkBuffers(gc, true, vramGCCh)
+		exists = false
+	}
+	if !exists {
+		gc = &gpuChunk{ChunkID: mesh.ChunkID}
+		gpuChunks[mesh.Coord] = gc
+		Cunt_ch++
+	}
+
+	gc.Version = mesh.Version
+	gc.Bounds = mesh.Bounds
+	gc.LOD = mesh.LOD
+	gc.Sections = mesh.Sections
+	gc.Lights = mesh.Lights
+	// Новый меш пишется через BufferSubData в свежие участки арен, а старые возвращаются
+	// в пул через VramGC в начале следующего кадра. Ни осиротения, ни постоянного отображения
+	// здесь нет: арена общая для всех чанков, а GL 4.1 не умеет BufferStorage. Запись мимо
+	// участков, которые читают рисующие команды, обычно обходится без ожидания GPU; если же
+	// участок, освобождённый на прошлом кадре, занят заново, пока тот кадр ещё рисуется,
+	// драйвер может дождаться его конца.
+	releaseChunkBuffers(gc, false, vramGCCh)
+	if len(mesh.Indices) > 0 {
+		uploadChunkMesh(gc, mesh)
+	}
+	mesh.MarkUploaded()
+}
+
+// releaseChunkBuffers отдаёт участки чанка сборщику видеопамяти
+func releaseChunkBuffers(gc *gpuChunk, unloaded bool, vramGCCh chan ChunkAllocation) {
+	alloc := gc.Alloc
+	alloc.Unloaded = unloaded
+	if alloc.Vertices.Size > 0 || alloc.Indices.Size > 0 || unloaded {
+		vramGCCh <- alloc
+	}
+	gc.Alloc = ChunkAllocation{}
+	gc.IndicesCount = 0
+}
+
+// FreeChunkAllocation возвращает участки чанка в пул общих буферов
+func FreeChunkAllocation(alloc ChunkAllocation) {
+	if chunkVertexArena == nil {
+		return
+	}
+	chunkVertexArena.Free(alloc.Vertices)
+	chunkIndexArena.Free(alloc.Indices)
+}
+
+// ChunkArenaStats возвращает занятый и общий объём арен чанков в байтах
+func ChunkArenaStats() (used, capacity int) {
+	if chunkVertexArena == nil {
+		return 0, 0
+	}
+	used = chunkVertexArena.Used*chunkVertexArena.Unit + chunkIndexArena.Used*chunkIndexArena.Unit
+	capacity = chunkVertexArena.Capacity*chunkVertexArena.Unit + chunkIndexArena.Capacity*chunkIndexArena.Unit
+	return used, capacity
+}
+
+// chunkLODDescription — число загруженных чанков и треугольников на каждом уровне детализации
+func chunkLODDescription() string {
+	var chunks, triangles [world.MaxLOD + 1]int
+	for _, gc := range gpuChunks {
+		chunks[gc.LOD]++
+		triangles[gc.LOD] += int(gc.IndicesCount) / 3
+	}
+	parts := make([]string, len(chunks))
+	for lod := range chunks {
+		parts[lod] = fmt.Sprintf("%d: %d (%.1fK tris)", lod, chunks[lod], float64(triangles[lod])/1000)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// chunkModelMatrix — сдвиг чанка в мировые координаты (по минимальной точке его AABB)
+func chunkModelMatrix(gc *gpuChunk) mgl32.Mat4 {
+	return mgl32.Translate3D(gc.Bounds[0].X(), 0, gc.Bounds[0].Z())
+}
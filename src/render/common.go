@@ -1,7 +1,7 @@
 package render
 
 import (
-	"engine/src/config"
+	"engine/src/profiler"
 	"engine/src/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -10,40 +10,11 @@ import (
 
 var (
 	Cunt_ch int
-)
-
-func setUniformMatrix4fv(program uint32, name string, matrix mgl32.Mat4) {
-	loc := gl.GetUniformLocation(program, gl.Str(name+"\x00"))
-	gl.UniformMatrix4fv(loc, 1, false, &matrix[0])
-}
-
-func setupCommonUniforms(program uint32, cameraPos mgl32.Vec3, Config *config.Config) {
-	// Цвет/интенсивность света, ambient и т.д.
-	lightColorLoc := gl.GetUniformLocation(program, gl.Str("lightColor\x00"))
-	gl.Uniform3f(lightColorLoc, 1.0, 1.0, 1.0)
-
-	ambientColorLoc := gl.GetUniformLocation(program, gl.Str("ambientColor\x00"))
-	gl.Uniform3f(ambientColorLoc, 0.2, 0.2, 0.2)
-
-	viewPosLoc := gl.GetUniformLocation(program, gl.Str("viewPos\x00"))
-	gl.Uniform3f(viewPosLoc, cameraPos.X(), cameraPos.Y(), cameraPos.Z())
 
-	// Параметры блика
-	shininessLoc := gl.GetUniformLocation(program, gl.Str("shininess\x00"))
-	gl.Uniform1f(shininessLoc, 32.0)
-
-	specStrLoc := gl.GetUniformLocation(program, gl.Str("specularStrength\x00"))
-	gl.Uniform1f(specStrLoc, 0.5)
-
-	// Параметры тумана
-	fogStartLoc := gl.GetUniformLocation(program, gl.Str("fogStart\x00"))
-	fogEndLoc := gl.GetUniformLocation(program, gl.Str("fogEnd\x00"))
-	fogColorLoc := gl.GetUniformLocation(program, gl.Str("fogColor\x00"))
-
-	gl.Uniform1f(fogStartLoc, Config.FogStartLoc)
-	gl.Uniform1f(fogEndLoc, Config.FogEndLoc)
-	gl.Uniform3f(fogColorLoc, 0.6, 0.7, 0.9)
-}
+	// Цвет и дальность тумана под водой
+	underwaterColor  = [3]float32{0.05, 0.25, 0.45}
+	underwaterFogEnd = float32(48.0)
+)
 
 func safeDeleteBuffers(vao, vbo, ebo *uint32) {
 	if *vao != 0 {
@@ -72,45 +43,66 @@ func calculateFrustumPlanes(view, projection mgl32.Mat4) [6]mgl32.Vec4 {
 	}
 }
 
-func updateChunkBuffers(chunk *world.Chunk) {
-	gl.BindVertexArray(chunk.VAO)
+// ensureChunkArenas лениво создаёт общие буферы чанков и их VAO
+func ensureChunkArenas() {
+	if chunkVAO != 0 {
+		return
+	}
+	gl.GenVertexArrays(1, &chunkVAO)
+	chunkVertexArena = newBufferArena(gl.ARRAY_BUFFER, chunkVertexStride, chunkArenaInitVertices)
+	chunkIndexArena = newBufferArena(gl.ELEMENT_ARRAY_BUFFER, 4, chunkArenaInitIndices)
+	bindChunkArenas()
+}
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, chunk.VBO)
-	gl.BufferSubData(gl.ARRAY_BUFFER, 0,
-		len(chunk.Vertices)*4, gl.Ptr(chunk.Vertices))
+// bindChunkArenas привязывает буферы арен к VAO чанков (заново после роста арены)
+func bindChunkArenas() {
+	gl.BindVertexArray(chunkVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, chunkVertexArena.Buffer)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, chunkIndexArena.Buffer)
 
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, chunk.EBO)
-	gl.BufferSubData(gl.ELEMENT_ARRAY_BUFFER, 0,
-		len(chunk.Indices)*4, gl.Ptr(chunk.Indices))
+	setupChunkVertexAttribs()
+	bindChunkOffsets()
+	gl.BindVertexArray(0)
+}
 
-	chunk.UpdateBuf = false
+// setupChunkVertexAttribs описывает формат вершин меша для привязанных VAO и ARRAY_BUFFER:
+// позиция (0) + нормаль (1) + цвет (2) + материал (4) + текстурные координаты и слой (5)
+func setupChunkVertexAttribs() {
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, chunkVertexStride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, chunkVertexStride, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, chunkVertexStride, gl.PtrOffset(6*4))
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointer(4, 1, gl.FLOAT, false, chunkVertexStride, gl.PtrOffset(9*4))
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointer(5, 3, gl.FLOAT, false, chunkVertexStride, gl.PtrOffset(10*4))
+	gl.EnableVertexAttribArray(5)
 }
 
-func createChunkBuffers(chunk *world.Chunk) {
-	var vao, vbo, ebo uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.BindVertexArray(vao)
+// uploadChunkMesh выделяет чанку участки под новый меш нужного размера и заливает их
+func uploadChunkMesh(gc *gpuChunk, mesh *world.ChunkMesh) {
+	ensureChunkArenas()
 
-	gl.GenBuffers(1, &vbo)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-	gl.BufferData(gl.ARRAY_BUFFER,
-		len(chunk.Vertices)*4, gl.Ptr(chunk.Vertices), gl.STATIC_DRAW)
+	vertexCount := len(mesh.Vertices) / world.VertexFloats
+	vertices, grewV := chunkVertexArena.Alloc(vertexCount)
+	indices, grewI := chunkIndexArena.Alloc(len(mesh.Indices))
+	if grewV || grewI {
+		bindChunkArenas()
+	}
 
-	gl.GenBuffers(1, &ebo)
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER,
-		len(chunk.Indices)*4, gl.Ptr(chunk.Indices), gl.STATIC_DRAW)
+	chunkVertexArena.Upload(vertices, mesh.Vertices, len(mesh.Vertices)*4)
+	chunkIndexArena.Upload(indices, mesh.Indices, len(mesh.Indices)*4)
 
-	// Позиция (0) + нормаль (1) + цвет (2)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 9*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 9*4, gl.PtrOffset(3*4))
-	gl.EnableVertexAttribArray(1)
-	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, 9*4, gl.PtrOffset(6*4))
-	gl.EnableVertexAttribArray(2)
+	gc.Alloc = ChunkAllocation{Vertices: vertices, Indices: indices}
+	gc.IndicesCount = int32(len(mesh.Indices))
+}
 
-	chunk.VAO, chunk.VBO, chunk.EBO = vao, vbo, ebo
-	chunk.CreateBuf = false
+// drawChunk рисует чанк из общих буферов (VAO чанков должен быть привязан)
+func drawChunk(gc *gpuChunk) {
+	gl.DrawElementsBaseVertex(gl.TRIANGLES, gc.IndicesCount, gl.UNSIGNED_INT,
+		gl.PtrOffset(gc.Alloc.Indices.Offset*4), int32(gc.Alloc.Vertices.Offset))
+	profiler.CountDraw(int(gc.IndicesCount) / 3)
 }
 
 func isChunkVisible(frustumPlanes [6]mgl32.Vec4, chunkBounds [2]mgl32.Vec3) bool {
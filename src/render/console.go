@@ -0,0 +1,60 @@
+package render
+
+import (
+	"engine/src/console"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Размеры строк консоли в пикселях
+const (
+	consoleLineHeight = 18
+	consoleMargin     = 8
+)
+
+// RenderConsole отрисовывает вывод консоли и, если она открыта, строку ввода внизу экрана
+func RenderConsole(window *glfw.Window, rectProgram, textProgram *ShaderProgram, c *console.Console) {
+	messages := c.Messages()
+	if !c.Open && len(messages) == 0 {
+		return
+	}
+	width, height := window.GetSize()
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	orthoProjection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
+
+	// Строка ввода — у нижнего края, вывод — над ней снизу вверх
+	inputY := float32(height - consoleMargin - consoleLineHeight)
+	if c.Open {
+		rectProgram.Use()
+		rectProgram.SetMat4("ortho", orthoProjection)
+		renderFilledRect(rectProgram, consoleMargin, inputY, float32(width-2*consoleMargin), consoleLineHeight, [4]float32{0, 0, 0, 0.6})
+	}
+
+	// Строки ставятся в очередь и рисуются одним вызовом, по центру своей строки консоли
+	textTop := (consoleLineHeight - textLineHeight(hudTextSize)) / 2
+	textAt := func(text string, y float32, color [4]float32) {
+		drawText(text, consoleMargin+4, y+textTop, hudTextSize, color, 0)
+	}
+
+	if c.Open {
+		textAt("> "+c.Line()+"_", inputY, [4]float32{1, 1, 1, 1})
+	}
+	for i := range messages {
+		m := messages[len(messages)-1-i]
+		color := [4]float32{0.85, 0.85, 0.85, 1}
+		if m.Error {
+			color = [4]float32{1, 0.45, 0.4, 1}
+		}
+		textAt(m.Text, inputY-float32((i+1)*consoleLineHeight), color)
+	}
+	flushText(window, textProgram)
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+}
@@ -0,0 +1,386 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/player"
+	"engine/src/profiler"
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Конвейеры отрисовки, выбираемые в конфиге (RenderPipeline)
+const (
+	PipelineForward  = "forward"
+	PipelineDeferred = "deferred"
+)
+
+// deferredPipeline — сцена рисуется через G-буфер. Выбирается при запуске в CreateDeferred.
+var deferredPipeline bool
+
+// gBuffer — цели геометрического прохода: альбедо, нормаль, материал и глубина
+type gBuffer struct {
+	FBO           uint32
+	Albedo        uint32 // RGBA8: цвет поверхности (уже с учётом намокания)
+	Normal        uint32 // RGBA16F: мировая нормаль
+	Material      uint32 // RGBA8: r — сила блика / 2, g — блеск / 256, b — свечение
+	Depth         uint32 // DEPTH_COMPONENT24: глубина, по ней восстанавливается позиция
+	Width, Height int32
+}
+
+// colorTarget — framebuffer с одной текстурой цвета RGBA16F: накопитель освещения,
+// цели постобработки
+type colorTarget struct {
+	FBO           uint32
+	Texture       uint32
+	Width, Height int32
+}
+
+var (
+	gbuffer        gBuffer
+	lightBuffer    colorTarget // накопитель освещения: солнце, затем точечные источники поверх
+	fullscreenVAO  uint32
+	pointLightVAO  uint32
+	pointLightVBO  uint32
+	pointLightData []float32
+
+	gBufferProgram    *ShaderProgram
+	sunLightProgram   *ShaderProgram
+	pointLightProgram *ShaderProgram
+	compositeProgram  *ShaderProgram
+
+	lastPointLights int // источников в последнем кадре, для отладочной панели
+)
+
+// Точечный источник в буфере инстансов: позиция и радиус, цвет
+const pointLightFloats = 7
+
+// Текстурные блоки G-буфера в проходах освещения (1 — карта теней)
+const (
+	gAlbedoUnit   = 6
+	gNormalUnit   = 7
+	gMaterialUnit = 8
+	gDepthUnit    = 9
+	lightUnit     = 10
+)
+
+// CreateDeferred включает отложенный конвейер, если его выбрал конфиг, и создаёт его
+// цели и шейдеры. Иначе сцена рисуется прямым проходом, как раньше.
+func CreateDeferred(Config *config.Config) {
+	switch Config.RenderPipeline {
+	case PipelineDeferred:
+	case PipelineForward, "":
+		return
+	default:
+		fmt.Printf("Unknown render pipeline %q, using %s\n", Config.RenderPipeline, PipelineForward)
+		return
+	}
+
+	// Отложенный конвейер работает, только если собрались все его шейдеры
+	if err := compileDeferredShaders(); err != nil {
+		fmt.Printf("Error compiling deferred shaders, using %s pipeline: %v\n", PipelineForward, err)
+		return
+	}
+	deferredPipeline = true
+
+	width, height := int32(Config.Width), int32(Config.Height)
+	gbuffer = createGBuffer(width, height)
+	lightBuffer = createColorTarget(width, height, gl.NEAREST)
+
+	ensureFullscreenVAO()
+
+	// Инстансы точечных источников: (позиция, радиус) на location 0, цвет на location 1
+	gl.GenVertexArrays(1, &pointLightVAO)
+	gl.BindVertexArray(pointLightVAO)
+	gl.GenBuffers(1, &pointLightVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, pointLightVBO)
+	gl.VertexAttribPointer(0, 4, gl.FLOAT, false, pointLightFloats*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribDivisor(0, 1)
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, pointLightFloats*4, gl.PtrOffset(4*4))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribDivisor(1, 1)
+	gl.BindVertexArray(0)
+}
+
+func compileDeferredShaders() error {
+	var err error
+	if gBufferProgram, err = compileGBufferShader(); err != nil {
+		return fmt.Errorf("G-buffer: %w", err)
+	}
+	if sunLightProgram, err = compileSunLightShader(); err != nil {
+		return fmt.Errorf("sun light: %w", err)
+	}
+	if pointLightProgram, err = compilePointLightShader(); err != nil {
+		return fmt.Errorf("point light: %w", err)
+	}
+	if compositeProgram, err = compileCompositeShader(); err != nil {
+		return fmt.Errorf("composite: %w", err)
+	}
+	return nil
+}
+
+func createGBuffer(width, height int32) gBuffer {
+	g := gBuffer{Width: width, Height: height}
+	gl.GenFramebuffers(1, &g.FBO)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, g.FBO)
+
+	g.Albedo = createTargetTexture(width, height, gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE)
+	g.Normal = createTargetTexture(width, height, gl.RGBA16F, gl.RGBA, gl.FLOAT)
+	g.Material = createTargetTexture(width, height, gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE)
+	g.Depth = createTargetTexture(width, height, gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, g.Albedo, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT1, gl.TEXTURE_2D, g.Normal, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT2, gl.TEXTURE_2D, g.Material, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, g.Depth, 0)
+
+	drawBuffers := []uint32{gl.COLOR_ATTACHMENT0, gl.COLOR_ATTACHMENT1, gl.COLOR_ATTACHMENT2}
+	gl.DrawBuffers(int32(len(drawBuffers)), &drawBuffers[0])
+
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("G-buffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return g
+}
+
+// createColorTarget создаёт framebuffer с текстурой RGBA16F; filter — фильтрация при чтении
+// (NEAREST для попиксельных проходов, LINEAR для размытия и FXAA)
+func createColorTarget(width, height int32, filter int32) colorTarget {
+	t := colorTarget{Width: width, Height: height}
+	gl.GenFramebuffers(1, &t.FBO)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.FBO)
+	t.Texture = createTargetTexture(width, height, gl.RGBA16F, gl.RGBA, gl.FLOAT)
+	if filter != gl.NEAREST {
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, filter)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+	}
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.Texture, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("Color target framebuffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return t
+}
+
+// createTargetTexture — текстура цели отрисовки без фильтрации: проходы читают её попиксельно
+func createTargetTexture(width, height int32, internalFormat int32, format, xtype uint32) uint32 {
+	var tex uint32
+	gl.GenTextures(1, &tex)
+	gl.BindTexture(gl.TEXTURE_2D, tex)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, width, height, 0, format, xtype, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	return tex
+}
+
+// renderDeferred рисует непрозрачный мир через G-буфер в буфер сцены:
+// геометрия → освещение солнцем с тенями и точечными источниками → композиция с небом
+// и туманом → вода прямым проходом поверх. Глубина G-буфера переносится в буфер сцены,
+// поэтому полупрозрачное после него рисуется с обычным тестом глубины.
+// Камеру, свет и туман проходы берут из блока FrameData, заполненного в RenderScene.
+func renderDeferred(
+	program *ShaderProgram,
+	config *config.Config,
+	cameraObj *player.Camera,
+	view, projection mgl32.Mat4,
+	eye mgl32.Vec3,
+	sky SkyState,
+	underwater bool,
+) {
+	frustumPlanes := calculateFrustumPlanes(view, projection)
+	invViewProjection := projection.Mul4(view).Inv()
+
+	// (1) Геометрия: альбедо, нормали, материал и глубина
+	profiler.Begin("opaque")
+	gl.BindFramebuffer(gl.FRAMEBUFFER, gbuffer.FBO)
+	gl.Viewport(0, 0, gbuffer.Width, gbuffer.Height)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	gBufferProgram.Use()
+	setupWeatherUniforms(gBufferProgram, sky)
+	bindBlockTextures(gBufferProgram)
+	drawChunks(gBufferProgram, func(gc *gpuChunk) bool {
+		return isChunkDrawn(frustumPlanes, gc)
+	})
+	if cameraObj.Mode == player.ThirdPerson {
+		renderPlayerModel(gBufferProgram, cameraObj)
+	}
+	profiler.End()
+
+	// Затенение ambient по глубине и нормалям G-буфера
+	if ssaoActive(config) {
+		profiler.Begin("ssao")
+		computeSSAO(gbuffer.Depth, gbuffer.Normal, view, projection, config)
+		profiler.End()
+	}
+
+	// (2) Освещение: полноэкранный проход солнца, затем объёмы точечных источников
+	profiler.Begin("lighting")
+	gl.BindFramebuffer(gl.FRAMEBUFFER, lightBuffer.FBO)
+	gl.Viewport(0, 0, gbuffer.Width, gbuffer.Height)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+
+	sunLightProgram.Use()
+	bindGBuffer(sunLightProgram)
+	sunLightProgram.SetMat4("invViewProjection", invViewProjection)
+	setupShadowUniforms(sunLightProgram)
+	setupAOUniforms(sunLightProgram, ssaoActive(config))
+	gl.BindVertexArray(fullscreenVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	profiler.CountDraw(1)
+
+	renderPointLights(config, invViewProjection, eye, frustumPlanes)
+
+	// (3) Композиция в буфер сцены: небо, освещённая сцена с туманом и её глубина
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sceneFramebuffer())
+	gl.Viewport(0, 0, int32(config.Width), int32(config.Height))
+	if !underwater {
+		renderSky(view, projection, eye, sky, postProcessHDR())
+	}
+
+	compositeProgram.Use()
+	bindGBuffer(compositeProgram)
+	compositeProgram.SetTexture("lightMap", lightUnit, gl.TEXTURE_2D, lightBuffer.Texture)
+	compositeProgram.SetMat4("invViewProjection", invViewProjection)
+
+	// Пиксели неба отбрасываются, остальные записывают глубину G-буфера
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthFunc(gl.ALWAYS)
+	gl.DepthMask(true)
+	gl.BindVertexArray(fullscreenVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	profiler.CountDraw(1)
+	gl.DepthFunc(gl.LESS)
+	gl.BindVertexArray(0)
+	profiler.End()
+
+	// (4) Полупрозрачное: вода основным шейдером с отражением и преломлением
+	profiler.Begin("translucent")
+	renderWorldPass(program, config, view, projection, eye, sky, passTranslucent, underwater)
+	profiler.End()
+}
+
+// bindGBuffer привязывает текстуры G-буфера к программе прохода освещения
+func bindGBuffer(program *ShaderProgram) {
+	targets := []struct {
+		name    string
+		unit    uint32
+		texture uint32
+	}{
+		{"gAlbedo", gAlbedoUnit, gbuffer.Albedo},
+		{"gNormal", gNormalUnit, gbuffer.Normal},
+		{"gMaterial", gMaterialUnit, gbuffer.Material},
+		{"gDepth", gDepthUnit, gbuffer.Depth},
+	}
+	for _, t := range targets {
+		program.SetTexture(t.name, t.unit, gl.TEXTURE_2D, t.texture)
+	}
+}
+
+// renderPointLights складывает в накопитель свет видимых светящихся блоков.
+// Каждый источник рисуется кубом своего радиуса (задними гранями — так куб виден и изнутри),
+// фрагментный шейдер освещает только пиксели G-буфера в пределах радиуса.
+// Ближайшие к камере источники важнее: при превышении MaxPointLights отбрасываются дальние.
+func renderPointLights(config *config.Config, invViewProjection mgl32.Mat4, eye mgl32.Vec3, frustumPlanes [6]mgl32.Vec4) {
+	type visibleLight struct {
+		index  int
+		distSq float32
+	}
+	pointLightData = pointLightData[:0]
+	var visible []visibleLight
+	for _, gc := range gpuChunks {
+		if len(gc.Lights) == 0 || !isChunkVisible(frustumPlanes, gc.Bounds) {
+			continue
+		}
+		for _, light := range gc.Lights {
+			if !isSphereVisible(frustumPlanes, light.Position, light.Radius) {
+				continue
+			}
+			visible = append(visible, visibleLight{index: len(pointLightData) / pointLightFloats, distSq: light.Position.Sub(eye).LenSqr()})
+			pointLightData = append(pointLightData,
+				light.Position.X(), light.Position.Y(), light.Position.Z(), light.Radius,
+				light.Color.X(), light.Color.Y(), light.Color.Z())
+		}
+	}
+	if len(visible) > config.MaxPointLights {
+		sort.Slice(visible, func(i, j int) bool { return visible[i].distSq < visible[j].distSq })
+		nearest := make([]float32, 0, config.MaxPointLights*pointLightFloats)
+		for _, v := range visible[:config.MaxPointLights] {
+			nearest = append(nearest, pointLightData[v.index*pointLightFloats:(v.index+1)*pointLightFloats]...)
+		}
+		pointLightData = nearest
+	}
+	lastPointLights = len(pointLightData) / pointLightFloats
+	if lastPointLights == 0 {
+		return
+	}
+
+	pointLightProgram.Use()
+	bindGBuffer(pointLightProgram)
+	pointLightProgram.SetMat4("invViewProjection", invViewProjection)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, pointLightVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(pointLightData)*4, gl.Ptr(pointLightData), gl.STREAM_DRAW)
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+	gl.Enable(gl.CULL_FACE)
+	gl.CullFace(gl.FRONT)
+	gl.BindVertexArray(pointLightVAO)
+	gl.DrawArraysInstanced(gl.TRIANGLES, 0, 36, int32(lastPointLights))
+	profiler.CountDraw(12 * lastPointLights)
+	gl.BindVertexArray(0)
+	gl.CullFace(gl.BACK)
+	gl.Disable(gl.CULL_FACE)
+	gl.Disable(gl.BLEND)
+}
+
+// isSphereVisible — сфера хотя бы частично внутри пирамиды видимости
+func isSphereVisible(frustumPlanes [6]mgl32.Vec4, center mgl32.Vec3, radius float32) bool {
+	for _, plane := range frustumPlanes {
+		normal := plane.Vec3()
+		if normal.Dot(center)+plane.W() < -radius*normal.Len() {
+			return false
+		}
+	}
+	return true
+}
+
+// pipelineName — описание конвейера для отладочной панели
+func pipelineName() string {
+	if deferredPipeline {
+		return fmt.Sprintf("%s (%d point lights)", PipelineDeferred, lastPointLights)
+	}
+	return PipelineForward
+}
+
+// compileGBufferShader — геометрический проход: вершинный шейдер основного, фрагментный пишет
+// поверхность в G-буфер. Вода отбрасывается, её рисует прямой проход полупрозрачного.
+func compileGBufferShader() (*ShaderProgram, error) {
+	return loadProgram("gbuffer", "geometry.vert", "gbuffer.frag")
+}
+
+// compileSunLightShader — освещение солнцем или луной с каскадными тенями и ambient
+func compileSunLightShader() (*ShaderProgram, error) {
+	return loadProgram("deferred_sun", "fullscreen.vert", "deferred_sun.frag")
+}
+
+// compilePointLightShader — объёмы точечных источников: куб радиуса источника,
+// свет гаснет к границе радиуса. Теней от точечных источников нет.
+func compilePointLightShader() (*ShaderProgram, error) {
+	return loadProgram("deferred_point", "deferred_point.vert", "deferred_point.frag")
+}
+
+// compileCompositeShader — накопленный свет с туманом по расстоянию до камеры
+// и перенос глубины G-буфера в буфер сцены
+func compileCompositeShader() (*ShaderProgram, error) {
+	return loadProgram("deferred_composite", "fullscreen.vert", "deferred_composite.frag")
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"engine/src/profiler"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// График времени кадра в правом нижнем углу: столбец на кадр истории профилировщика,
+// зелёный — до 60 FPS, жёлтый — до 30, красный — медленнее. Линии отмечают 16.7 и 33.3 мс.
+
+const (
+	frameGraphWidth  = 300 // столбцов (последних кадров)
+	frameGraphHeight = 100 // пикселей на frameGraphScale мс
+	frameGraphScale  = 50.0
+	frameGraphMargin = 10
+)
+
+// Буферы прямоугольников: пишутся заново каждый вызов, а не создаются на каждый прямоугольник
+var rectVAO, rectVBO uint32
+
+// RenderFrameGraph рисует график времени последних кадров шейдером перекрестия
+func RenderFrameGraph(window *glfw.Window, program *ShaderProgram) {
+	if !program.Ready() {
+		return
+	}
+	width, height := window.GetSize()
+	frames := profiler.Frames()
+	if len(frames) > frameGraphWidth {
+		frames = frames[len(frames)-frameGraphWidth:]
+	}
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	program.Use()
+	program.SetMat4("ortho", mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1))
+
+	left := float32(width - frameGraphWidth - frameGraphMargin)
+	bottom := float32(height - frameGraphMargin)
+	renderFilledRect(program, left, bottom-frameGraphHeight, frameGraphWidth, frameGraphHeight, [4]float32{0, 0, 0, 0.5})
+
+	// Столбцы группируются по цвету: один вызов отрисовки на цвет
+	var fast, slow, stalled []float32
+	for i, frame := range frames {
+		h := min(float32(frame.Millis/frameGraphScale)*frameGraphHeight, frameGraphHeight)
+		rect := [4]float32{left + float32(i), bottom - h, 1, h}
+		switch {
+		case frame.Millis <= 1000.0/60:
+			fast = appendRect(fast, rect)
+		case frame.Millis <= 1000.0/30:
+			slow = appendRect(slow, rect)
+		default:
+			stalled = appendRect(stalled, rect)
+		}
+	}
+	renderRects(program, fast, [4]float32{0.2, 0.9, 0.3, 0.9})
+	renderRects(program, slow, [4]float32{1.0, 0.8, 0.2, 0.9})
+	renderRects(program, stalled, [4]float32{1.0, 0.25, 0.2, 0.9})
+
+	var marks []float32
+	for _, ms := range []float64{1000.0 / 60, 1000.0 / 30} {
+		y := bottom - float32(ms/frameGraphScale)*frameGraphHeight
+		marks = appendRect(marks, [4]float32{left, y, frameGraphWidth, 1})
+	}
+	renderRects(program, marks, [4]float32{1, 1, 1, 0.4})
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+}
+
+// appendRect добавляет два треугольника прямоугольника {x, y, ширина, высота}
+func appendRect(vertices []float32, r [4]float32) []float32 {
+	x0, y0, x1, y1 := r[0], r[1], r[0]+r[2], r[1]+r[3]
+	return append(vertices,
+		x0, y0, 0, x1, y0, 0, x1, y1, 0,
+		x1, y1, 0, x0, y1, 0, x0, y0, 0,
+	)
+}
+
+// renderRects рисует одним вызовом прямоугольники из appendRect цветом color.
+// Программа и матрица ortho должны быть уже установлены.
+func renderRects(program *ShaderProgram, vertices []float32, color [4]float32) {
+	if len(vertices) == 0 {
+		return
+	}
+	if rectVAO == 0 {
+		gl.GenVertexArrays(1, &rectVAO)
+		gl.GenBuffers(1, &rectVBO)
+		gl.BindVertexArray(rectVAO)
+		gl.BindBuffer(gl.ARRAY_BUFFER, rectVBO)
+		// Позиция (location = 0)
+		gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
+		gl.EnableVertexAttribArray(0)
+	}
+	program.SetVec4("crosshairColor", color)
+
+	gl.BindVertexArray(rectVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, rectVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STREAM_DRAW)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(len(vertices)/3))
+	profiler.CountDraw(len(vertices) / 9)
+	gl.BindVertexArray(0)
+}
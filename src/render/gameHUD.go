@@ -1,13 +1,19 @@
 package render
 
 import (
+	"engine/src/player"
+	"engine/src/profiler"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
 // RenderCrosshair отрисовывает простое перекрестие в центре экрана.
-func RenderCrosshair(window *glfw.Window, program uint32) {
+func RenderCrosshair(window *glfw.Window, program *ShaderProgram) {
+	if !program.Ready() {
+		return
+	}
 	width, height := window.GetSize()
 
 	// Включаем альфа-смешивание (прозрачность)
@@ -20,16 +26,13 @@ func RenderCrosshair(window *glfw.Window, program uint32) {
 	// Устанавливаем ортографическую проекцию
 	orthoProjection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
 
-	gl.UseProgram(program)
+	program.Use()
 
 	// Установка униформ переменной ортографической матрицы
-	orthoLoc := gl.GetUniformLocation(program, gl.Str("ortho\x00"))
-	gl.UniformMatrix4fv(orthoLoc, 1, false, &orthoProjection[0])
+	program.SetMat4("ortho", orthoProjection)
 
 	// Установка цвета перекрестия
-	color := [4]float32{1.0, 1.0, 1.0, 1.0} // Белый цвет
-	colorLoc := gl.GetUniformLocation(program, gl.Str("crosshairColor\x00"))
-	gl.Uniform4fv(colorLoc, 1, &color[0])
+	program.SetVec4("crosshairColor", mgl32.Vec4{1.0, 1.0, 1.0, 1.0}) // Белый цвет
 
 	// Рендерим перекрестие
 	renderCrosshairQuad(float32(width)/2, float32(height)/2, 10, 2)
@@ -39,6 +42,44 @@ func RenderCrosshair(window *glfw.Window, program uint32) {
 	gl.Disable(gl.BLEND)
 }
 
+// RenderStatusBars отрисовывает полоски здоровья и воздуха внизу экрана.
+// Полоска воздуха показывается, только когда запас неполный.
+func RenderStatusBars(window *glfw.Window, program *ShaderProgram, stats player.Stats) {
+	width, height := window.GetSize()
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	orthoProjection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
+	program.Use()
+	program.SetMat4("ortho", orthoProjection)
+
+	const barWidth, barHeight, barGap = float32(240), float32(12), float32(6)
+	x := (float32(width) - barWidth) / 2
+	y := float32(height) - 40
+
+	renderStatusBar(program, x, y, barWidth, barHeight, stats.Health/stats.MaxHealth, [4]float32{0.85, 0.1, 0.1, 0.9})
+	if stats.Air < stats.MaxAir {
+		y -= barHeight + barGap
+		renderStatusBar(program, x, y, barWidth, barHeight, stats.Air/stats.MaxAir, [4]float32{0.2, 0.5, 1.0, 0.9})
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+}
+
+// renderStatusBar рисует фон полоски и её заполненную часть (fraction в [0, 1])
+func renderStatusBar(program *ShaderProgram, x, y, width, height, fraction float32, color [4]float32) {
+	if fraction < 0 {
+		fraction = 0
+	} else if fraction > 1 {
+		fraction = 1
+	}
+	renderFilledRect(program, x-2, y-2, width+4, height+4, [4]float32{0, 0, 0, 0.6})
+	renderFilledRect(program, x, y, width*fraction, height, color)
+}
+
 // renderCrosshairQuad отрисовывает перекрестие в виде двух пересекающихся линий.
 func renderCrosshairQuad(x, y, size, thickness float32) {
 	vertices := []float32{
@@ -79,6 +120,53 @@ func renderCrosshairQuad(x, y, size, thickness float32) {
 
 	// Рисуем
 	gl.DrawElements(gl.LINES, int32(len(indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	profiler.CountDraw(0)
+
+	// Освобождаем
+	gl.BindVertexArray(0)
+	gl.DeleteBuffers(1, &vbo)
+	gl.DeleteBuffers(1, &ebo)
+	gl.DeleteVertexArrays(1, &vao)
+}
+
+// renderFilledRect отрисовывает залитый прямоугольник шейдером перекрестия.
+// Программа и матрица ortho должны быть уже установлены.
+func renderFilledRect(program *ShaderProgram, x, y, width, height float32, color [4]float32) {
+	if !program.Ready() {
+		return
+	}
+	program.SetVec4("crosshairColor", color)
+
+	vertices := []float32{
+		x, y, 0.0,
+		x + width, y, 0.0,
+		x + width, y + height, 0.0,
+		x, y + height, 0.0,
+	}
+	indices := []uint32{
+		0, 1, 2,
+		2, 3, 0,
+	}
+
+	var vao, vbo, ebo uint32
+	gl.GenVertexArrays(1, &vao)
+	gl.GenBuffers(1, &vbo)
+	gl.GenBuffers(1, &ebo)
+
+	gl.BindVertexArray(vao)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+
+	// Позиция (location = 0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	gl.DrawElements(gl.TRIANGLES, int32(len(indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	profiler.CountDraw(len(indices) / 3)
 
 	// Освобождаем
 	gl.BindVertexArray(0)
@@ -0,0 +1,133 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/profiler"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	gl43 "github.com/go-gl/gl/v4.3-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// multiDrawIndirect — доступен ли glMultiDrawElementsIndirect (GL 4.3+).
+// Иначе чанки рисуются по одному с uniform "model", как в GL 4.1.
+var multiDrawIndirect bool
+
+// Буферы команд и смещений чанков для multi-draw
+var (
+	chunkIndirectBuffer uint32
+	chunkOffsetBuffer   uint32
+	chunkCommands       []drawElementsIndirectCommand
+	chunkOffsets        []float32
+)
+
+// drawElementsIndirectCommand — раскладка команды из спецификации glMultiDrawElementsIndirect
+type drawElementsIndirectCommand struct {
+	Count         uint32
+	InstanceCount uint32
+	FirstIndex    uint32
+	BaseVertex    int32
+	BaseInstance  uint32
+}
+
+const drawCommandSize = 5 * 4
+
+// InitMultiDraw включает multi-draw indirect, если конфиг просит контекст 4.3+
+// и драйвер его действительно выдал. Вызывается после создания окна.
+func InitMultiDraw(Config *config.Config) {
+	multiDrawIndirect = false
+	if Config.ContextVersionMajor < 4 || (Config.ContextVersionMajor == 4 && Config.ContextVersionMinor < 3) {
+		return
+	}
+	var major, minor int32
+	gl.GetIntegerv(gl.MAJOR_VERSION, &major)
+	gl.GetIntegerv(gl.MINOR_VERSION, &minor)
+	if major < 4 || (major == 4 && minor < 3) {
+		return
+	}
+	if err := gl43.Init(); err != nil {
+		fmt.Println("Multi-draw indirect unavailable:", err)
+		return
+	}
+	multiDrawIndirect = true
+
+	gl.GenBuffers(1, &chunkIndirectBuffer)
+	gl.GenBuffers(1, &chunkOffsetBuffer)
+}
+
+// chunkDrawPath — описание способа отрисовки чанков для отладочной панели
+func chunkDrawPath() string {
+	if multiDrawIndirect {
+		return fmt.Sprintf("multi-draw indirect (%d commands)", len(chunkCommands))
+	}
+	return "per-chunk"
+}
+
+// bindChunkOffsets подключает к VAO чанков инстансный атрибут смещения (location 3)
+func bindChunkOffsets() {
+	if !multiDrawIndirect {
+		return
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, chunkOffsetBuffer)
+	gl.VertexAttribPointer(3, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribDivisor(3, 1)
+}
+
+// drawChunks рисует чанки, для которых visible возвращает true:
+// одной командой multi-draw на GL 4.3 или по одному чанку на GL 4.1.
+func drawChunks(program *ShaderProgram, visible func(gc *gpuChunk) bool) {
+	if !program.Ready() {
+		return
+	}
+	gl.BindVertexArray(chunkVAO)
+	modelLoc := program.Location("model")
+
+	if !multiDrawIndirect {
+		for _, gc := range gpuChunks {
+			if gc.IndicesCount == 0 || !visible(gc) {
+				continue
+			}
+			model := chunkModelMatrix(gc)
+			gl.UniformMatrix4fv(modelLoc, 1, false, &model[0])
+			drawChunk(gc)
+		}
+		return
+	}
+
+	chunkCommands = chunkCommands[:0]
+	chunkOffsets = chunkOffsets[:0]
+	triangles := 0
+	for _, gc := range gpuChunks {
+		if gc.IndicesCount == 0 || !visible(gc) {
+			continue
+		}
+		chunkCommands = append(chunkCommands, drawElementsIndirectCommand{
+			Count:         uint32(gc.IndicesCount),
+			InstanceCount: 1,
+			FirstIndex:    uint32(gc.Alloc.Indices.Offset),
+			BaseVertex:    int32(gc.Alloc.Vertices.Offset),
+			BaseInstance:  uint32(len(chunkCommands)),
+		})
+		chunkOffsets = append(chunkOffsets, gc.Bounds[0].X(), 0, gc.Bounds[0].Z())
+		triangles += int(gc.IndicesCount) / 3
+	}
+	if len(chunkCommands) == 0 {
+		return
+	}
+
+	// Смещения берутся из атрибута, поэтому model — единичная
+	identity := mgl32.Ident4()
+	gl.UniformMatrix4fv(modelLoc, 1, false, &identity[0])
+
+	// Буферы «осиротеваем» каждый проход: драйвер не ждёт предыдущих кадров
+	gl.BindBuffer(gl.ARRAY_BUFFER, chunkOffsetBuffer)
+	gl.BufferData(gl.ARRAY_BUFFER, len(chunkOffsets)*4, gl.Ptr(chunkOffsets), gl.STREAM_DRAW)
+	gl.BindBuffer(gl43.DRAW_INDIRECT_BUFFER, chunkIndirectBuffer)
+	gl.BufferData(gl43.DRAW_INDIRECT_BUFFER, len(chunkCommands)*drawCommandSize, gl.Ptr(chunkCommands), gl.STREAM_DRAW)
+
+	gl43.MultiDrawElementsIndirect(gl.TRIANGLES, gl.UNSIGNED_INT, gl.PtrOffset(0), int32(len(chunkCommands)), 0)
+	profiler.CountDraw(triangles)
+	gl.BindBuffer(gl43.DRAW_INDIRECT_BUFFER, 0)
+}
@@ -2,25 +2,18 @@ package render
 
 import (
 	"engine/src/player"
+	"engine/src/profiler"
 	"engine/src/world"
 	"fmt"
-	"image"
-	"image/draw"
-	"log"
 	"runtime"
+	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/go-gl/mathgl/mgl32"
-	"github.com/golang/freetype"
-	"github.com/golang/freetype/truetype"
-	"golang.org/x/image/font/gofont/goregular"
 )
 
 // RenderDebugHUD отрисовывает отладочную информацию в HUD.
-func RenderDebugHUD(window *glfw.Window, program uint32, debugInfo []string) {
-	width, height := window.GetSize()
-
+func RenderDebugHUD(window *glfw.Window, program *ShaderProgram, debugInfo []string) {
 	// Включаем альфа-смешивание (прозрачность)
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
@@ -28,151 +21,100 @@ func RenderDebugHUD(window *glfw.Window, program uint32, debugInfo []string) {
 	// Отключаем тест глубины, чтобы текст всегда отображался поверх
 	gl.Disable(gl.DEPTH_TEST)
 
-	// Устанавливаем ортографическую проекцию
-	orthoProjection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
-
-	gl.UseProgram(program)
-
-	// Установка униформ переменной ортографической матрицы
-	orthoLoc := gl.GetUniformLocation(program, gl.Str("ortho\x00"))
-	gl.UniformMatrix4fv(orthoLoc, 1, false, &orthoProjection[0])
-
-	// Установка цвета текста
-	color := [4]float32{1.0, 1.0, 1.0, 1.0} // Белый цвет
-	colorLoc := gl.GetUniformLocation(program, gl.Str("textColor\x00"))
-	gl.Uniform4fv(colorLoc, 1, &color[0])
-
-	// Привязываем текстурный юнит к тексту
-	textureLoc := gl.GetUniformLocation(program, gl.Str("textTexture\x00"))
-	gl.Uniform1i(textureLoc, 0) // TEXTURE0
-
-	// Рендерим каждую строку отладочной информации
-	for i, line := range debugInfo {
-		RenderText(line, 5, 15*(i+1), width, height, program, color)
-	}
+	// Все строки панели — один вызов отрисовки
+	drawText(strings.Join(debugInfo, "\n"), 10, 10, hudTextSize, [4]float32{1, 1, 1, 1}, 0)
+	flushText(window, program)
 
 	// Восстанавливаем настройки рендера
 	gl.Enable(gl.DEPTH_TEST)
 	gl.Disable(gl.BLEND)
 }
 
-// RenderText отрисовывает текстовую строку на экране.
-func RenderText(text string, x, y, screenWidth, screenHeight int, program uint32, color [4]float32) {
-	font, err := truetype.Parse(goregular.TTF)
-	if err != nil {
-		log.Fatalf("failed to parse font: %v", err)
-	}
-
-	// Создаём изображение для текста
-	img := image.NewRGBA(image.Rect(0, 0, 512, 512))
-	draw.Draw(img, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
-
-	c := freetype.NewContext()
-	c.SetDPI(72)
-	c.SetFont(font)
-	c.SetFontSize(15)
-	c.SetClip(img.Bounds())
-	c.SetDst(img)
-	c.SetSrc(image.White)
-
-	// Отрисовываем текст в изображение
-	pt := freetype.Pt(10, 10+int(c.PointToFixed(24)>>6))
-	_, err = c.DrawString(text, pt)
-	if err != nil {
-		log.Fatalf("failed to draw string: %v", err)
-	}
-
-	// Создаём текстуру OpenGL
-	var texture uint32
-	gl.GenTextures(1, &texture)
-	defer gl.DeleteTextures(1, &texture)
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
-
-	gl.TexImage2D(
-		gl.TEXTURE_2D, 0, gl.RGBA,
-		int32(img.Bounds().Dx()), int32(img.Bounds().Dy()),
-		0, gl.RGBA, gl.UNSIGNED_BYTE,
-		gl.Ptr(img.Pix),
-	)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-
-	// Рендерим прямоугольник с текстурой
-	renderTexturedQuad(texture, float32(x), float32(y), float32(img.Bounds().Dx()), float32(img.Bounds().Dy()))
-
-	// Удаляем текстуру после рендера
-	gl.DeleteTextures(1, &texture)
-}
-
-// renderTexturedQuad отрисовывает прямоугольник с текстурой.
-func renderTexturedQuad(texture uint32, x, y, width, height float32) {
-	vertices := []float32{
-		// Положение (x,y,z)    // Текс-коорд (u,v)
-		x, y, 0.0, 0.0, 0.0, // нижний левый
-		x + width, y, 0.0, 1.0, 0.0, // нижний правый
-		x + width, y + height, 0.0, 1.0, 1.0, // верхний правый
-		x, y + height, 0.0, 0.0, 1.0, // верхний левый
-	}
-
-	indices := []uint32{
-		0, 1, 2, // первый треугольник
-		2, 3, 0, // второй треугольник
-	}
-
-	var vao, vbo, ebo uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.GenBuffers(1, &vbo)
-	gl.GenBuffers(1, &ebo)
-
-	gl.BindVertexArray(vao)
-
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
-
-	// Позиция (location = 0)
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-
-	// Текстурные координаты (location = 1)
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
-	gl.EnableVertexAttribArray(1)
-
-	// Привязка текстуры (активный юнит уже TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
-
-	// Рисуем
-	gl.DrawElements(gl.TRIANGLES, int32(len(indices)), gl.UNSIGNED_INT, gl.PtrOffset(0))
-
-	// Освобождаем
-	gl.BindVertexArray(0)
-	gl.DeleteBuffers(1, &vbo)
-	gl.DeleteBuffers(1, &ebo)
-	gl.DeleteVertexArrays(1, &vao)
-}
-func Get_hud_info(deltaTime float64, worldObj *world.World, cameraObj *player.Camera) []string {
+func Get_hud_info(worldObj *world.World, cameraObj *player.Camera) []string {
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
-	// Замер видеопамяти
-	var totalVRAM, availableVRAM int32
-	gl.GetIntegerv(0x9048 /* GL_GPU_MEMORY_INFO_TOTAL_AVAILABLE_MEMORY_NVX */, &totalVRAM)       // Общее количество VRAM
-	gl.GetIntegerv(0x9049 /* GL_GPU_MEMORY_INFO_CURRENT_AVAILABLE_VIDMEM_NVX */, &availableVRAM) // Доступная VRAM
-	var usedVRAM int32
-	if totalVRAM > 0 {
-		usedVRAM = totalVRAM - availableVRAM
+	summary := profiler.Summarize()
+	fps := 0.0
+	if summary.AvgMillis > 0 {
+		fps = 1000 / summary.AvgMillis
 	}
-	return []string{
-		fmt.Sprintf("FPS: %.2f", 1.0/deltaTime),
+	queued, _, cancelled := worldObj.Scheduler.Stats()
+	states := worldObj.ChunkStates()
+	arenaUsed, arenaCapacity := ChunkArenaStats()
+	lines := []string{
+		fmt.Sprintf("FPS: %.1f (avg %.2f ms, p50 %.2f, p95 %.2f, p99 %.2f, max %.2f over %d frames)",
+			fps, summary.AvgMillis, summary.P50, summary.P95, summary.P99, summary.Max, summary.Frames),
+		fmt.Sprintf("Draw Calls: %d, Triangles: %.1fK", summary.DrawCalls, float64(summary.Triangles)/1000),
 		fmt.Sprintf("Camera Position: X=%.2f Y=%.2f Z=%.2f", cameraObj.Position.X(), cameraObj.Position.Y(), cameraObj.Position.Z()),
-		fmt.Sprintf("Chunks Loaded: %d", len(worldObj.Chunks)),
+		fmt.Sprintf("Chunks Loaded: %d", worldObj.ChunkCount()),
+		fmt.Sprintf("Chunk Pipeline: Queued: %d, Generating: %d, Meshing: %d, Uploaded: %d, Cancelled: %d",
+			queued, states[world.ChunkGenerating], states[world.ChunkMeshing], len(gpuChunks), cancelled),
+		fmt.Sprintf("GPU Uploads: Queued: %d, Last Frame: %d (%.1f KB, %.2f ms)",
+			lastUploadStats.Queued, lastUploadStats.Uploaded, float64(lastUploadStats.Bytes)/1024, lastUploadStats.Millis),
+		fmt.Sprintf("Chunk Arena: %.1f / %.1f MB", float64(arenaUsed)/1024/1024, float64(arenaCapacity)/1024/1024),
+		fmt.Sprintf("Chunk Draw: %s", chunkDrawPath()),
+		fmt.Sprintf("Chunk LOD: %s", chunkLODDescription()),
+		fmt.Sprintf("Occlusion: %s", occlusionDescription()),
+		fmt.Sprintf("Pipeline: %s", pipelineName()),
+		fmt.Sprintf("Post: %s", postProcessDescription()),
+		fmt.Sprintf("SSAO: %s", ssaoDescription()),
+		fmt.Sprintf("World Time: %s", worldObj.Clock.Now()),
+		fmt.Sprintf("Weather: %s", worldObj.Weather.Now()),
 		fmt.Sprintf("Allocated RAM: %.2f MB", float64(memStats.Alloc)/1024/1024),
 		fmt.Sprintf("Total Allocated RAM: %.2f MB", float64(memStats.TotalAlloc)/1024/1024),
 		fmt.Sprintf("System RAM: %.2f MB", float64(memStats.Sys)/1024/1024),
-		fmt.Sprintf("Total VRAM: %.2f MB", float64(totalVRAM)/1024),
-		fmt.Sprintf("Used VRAM: %.2f MB", float64(usedVRAM)/1024),
+		fmt.Sprintf("VRAM: %s", vramDescription()),
+		"Timings (CPU / GPU, ms):",
+	}
+	// Области профилировщика со сдвигом по вложенности
+	for _, scope := range summary.Scopes {
+		gpu := "-"
+		if scope.GPU >= 0 {
+			gpu = fmt.Sprintf("%.2f", scope.GPU)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s: %.2f / %s", strings.Repeat("    ", scope.Depth+1), scope.Name, scope.CPU, gpu))
+	}
+	return lines
+}
+
+// Способ узнать занятость видеопамяти: расширения NVIDIA и AMD, у остальных драйверов её не узнать
+const (
+	vramUnknown = iota
+	vramNVX
+	vramATI
+)
+
+var vramQuery = -1 // -1 — расширения ещё не проверялись
+
+// vramDescription — занятая и общая видеопамять (NVX_gpu_memory_info) или свободная (ATI_meminfo)
+func vramDescription() string {
+	if vramQuery < 0 {
+		vramQuery = vramUnknown
+		var count int32
+		gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+		for i := int32(0); i < count; i++ {
+			switch gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i))) {
+			case "GL_NVX_gpu_memory_info":
+				vramQuery = vramNVX
+			case "GL_ATI_meminfo":
+				if vramQuery == vramUnknown {
+					vramQuery = vramATI
+				}
+			}
+		}
+	}
+
+	switch vramQuery {
+	case vramNVX:
+		var total, available int32
+		gl.GetIntegerv(0x9048 /* GL_GPU_MEMORY_INFO_TOTAL_AVAILABLE_MEMORY_NVX */, &total)
+		gl.GetIntegerv(0x9049 /* GL_GPU_MEMORY_INFO_CURRENT_AVAILABLE_VIDMEM_NVX */, &available)
+		return fmt.Sprintf("%.2f / %.2f MB", float64(total-available)/1024, float64(total)/1024)
+	case vramATI:
+		var free [4]int32 // первое значение — свободный объём пула в КБ
+		gl.GetIntegerv(0x87FC /* GL_TEXTURE_FREE_MEMORY_ATI */, &free[0])
+		return fmt.Sprintf("%.2f MB free", float64(free[0])/1024)
 	}
+	return "n/a"
 }
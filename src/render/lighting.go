@@ -2,26 +2,140 @@ package render
 
 import (
 	"engine/src/config"
+	"engine/src/player"
+	"engine/src/world"
 	"math"
 
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-func GetLightProjection(Config *config.Config) mgl32.Mat4 {
-	size := Config.ShadowDist
-	near := float32(0.1)
-	far := float32(1000.0)
-	return mgl32.Ortho(-size, size, -size, size, near, far)
+// Максимум каскадов теней (размер массивов в шейдере)
+const maxShadowCascades = 4
+
+// Доля каждого каскада, на которой тень плавно смешивается со следующим
+const cascadeBlendFraction = 0.1
+
+// ShadowCascade — один каскад теней: матрица света, диапазон глубины вида и плоскости для отсечения чанков
+type ShadowCascade struct {
+	LightSpace mgl32.Mat4
+	Near, Far  float32 // глубина в пространстве камеры
+	Planes     [6]mgl32.Vec4
+}
+
+// ComputeShadowCascades делит фрустум камеры на каскады (практическая схема разбиения:
+// смесь логарифмического и равномерного) и строит для каждого ортографическую матрицу света.
+// lightDir — направление на источник света.
+func ComputeShadowCascades(cameraObj *player.Camera, lightDir mgl32.Vec3, Config *config.Config) []ShadowCascade {
+	count := Config.ShadowCascades
+	if count < 1 {
+		count = 1
+	}
+	if count > maxShadowCascades {
+		count = maxShadowCascades
+	}
+
+	near := Config.NearPlane
+	far := float32(math.Min(float64(Config.ShadowDist), float64(Config.FarPlane)))
+	view := cameraObj.GetViewMatrix()
+	fov := mgl32.DegToRad(cameraObj.FOV())
+	aspect := float32(Config.Width) / float32(Config.Height)
+	lightDir = lightDir.Normalize()
+
+	cascades := make([]ShadowCascade, count)
+	splitNear := near
+	for i := 0; i < count; i++ {
+		p := float64(i+1) / float64(count)
+		logSplit := float64(near) * math.Pow(float64(far/near), p)
+		uniSplit := float64(near) + float64(far-near)*p
+		splitFar := float32(float64(Config.ShadowSplitLambda)*logSplit + (1-float64(Config.ShadowSplitLambda))*uniSplit)
+
+		cascades[i] = cascadeForSlice(view, fov, aspect, splitNear, splitFar, lightDir, Config)
+		splitNear = splitFar
+	}
+	return cascades
 }
-func GetDynamicLightPos(playerPos mgl32.Vec3, timeOfDay float64) mgl32.Vec3 {
-	// Константы для настроек движения солнца
-	radius := float32(300.0)      // Радиус вращения
-	fixedHeight := float32(500.0) // Фиксированная высота солнца
 
-	// Вычисляем позицию солнца
-	x := playerPos.X() + radius*float32(math.Cos(timeOfDay))
-	y := playerPos.Y() + fixedHeight // Высота фиксирована
-	z := playerPos.Z() + radius*float32(math.Sin(timeOfDay))
+// cascadeForSlice строит матрицу света, охватывающую участок фрустума [near, far].
+// Берётся описанная сфера участка: её размер не зависит от поворота камеры,
+// а сдвиг матрицы округляется до текселя карты — тени не «дрожат» при движении.
+func cascadeForSlice(view mgl32.Mat4, fov, aspect, near, far float32, lightDir mgl32.Vec3, Config *config.Config) ShadowCascade {
+	inv := mgl32.Perspective(fov, aspect, near, far).Mul4(view).Inv()
+	var corners [8]mgl32.Vec3
+	var center mgl32.Vec3
+	i := 0
+	for _, x := range []float32{-1, 1} {
+		for _, y := range []float32{-1, 1} {
+			for _, z := range []float32{-1, 1} {
+				p := inv.Mul4x1(mgl32.Vec4{x, y, z, 1})
+				corners[i] = p.Vec3().Mul(1 / p.W())
+				center = center.Add(corners[i])
+				i++
+			}
+		}
+	}
+	center = center.Mul(1.0 / 8)
+
+	var radius float32
+	for _, c := range corners {
+		radius = float32(math.Max(float64(radius), float64(c.Sub(center).Len())))
+	}
+	radius = float32(math.Ceil(float64(radius)*16) / 16)
+
+	// Источник отодвигаем за сферу на высоту мира, чтобы в карту попадали и тени от гор вне фрустума
+	casterMargin := float32(Config.ChunkY)
+	up := mgl32.Vec3{0, 1, 0}
+	if math.Abs(float64(lightDir.Y())) > 0.99 {
+		up = mgl32.Vec3{0, 0, 1}
+	}
+	lightView := mgl32.LookAtV(center.Add(lightDir.Mul(radius+casterMargin)), center, up)
+	lightProj := mgl32.Ortho(-radius, radius, -radius, radius, 0, 2*radius+casterMargin)
+
+	// Привязка к сетке текселей
+	halfRes := float32(Config.ShadowResolution) / 2
+	origin := lightProj.Mul4(lightView).Mul4x1(mgl32.Vec4{0, 0, 0, 1})
+	ox, oy := origin.X()*halfRes, origin.Y()*halfRes
+	lightProj[12] += (float32(math.Round(float64(ox))) - ox) / halfRes
+	lightProj[13] += (float32(math.Round(float64(oy))) - oy) / halfRes
+
+	return ShadowCascade{
+		LightSpace: lightProj.Mul4(lightView),
+		Near:       near,
+		Far:        far,
+		Planes:     calculateFrustumPlanes(lightView, lightProj),
+	}
+}
+
+// setupShadowUniforms привязывает массив карт теней; матрицы и границы каскадов
+// лежат в блоке ShadowData (updateShadowUniforms)
+func setupShadowUniforms(program *ShaderProgram) {
+	program.SetTexture("shadowMap", 1, gl.TEXTURE_2D_ARRAY, depthMap)
+}
+
+// Расстояние от игрока до источника света (для позиции солнца в мире)
+const sunDistance = 500.0
+
+// SunDirection — направление на солнце по мировым часам. Часовой угол — от доли суток
+// (полдень — 0.5), склонение меняется по сезонам года из DaysPerYear суток; широта из конфига.
+// Восток — +X, север — −Z.
+func SunDirection(clock world.ClockState, Config *config.Config) mgl32.Vec3 {
+	hourAngle := 2 * math.Pi * (clock.Fraction - 0.5)
+
+	// Склонение: −23.44° в день зимнего солнцестояния (10 суток до начала года), +23.44° летом
+	declination := 0.0
+	if Config.DaysPerYear > 0 {
+		yearPhase := (float64(clock.Day%int64(Config.DaysPerYear)) + clock.Fraction + 10) / float64(Config.DaysPerYear)
+		declination = -23.44 * math.Pi / 180 * math.Cos(2*math.Pi*yearPhase)
+	}
+	latitude := Config.Latitude * math.Pi / 180
+
+	up := math.Sin(latitude)*math.Sin(declination) + math.Cos(latitude)*math.Cos(declination)*math.Cos(hourAngle)
+	east := -math.Cos(declination) * math.Sin(hourAngle)
+	north := math.Cos(latitude)*math.Sin(declination) - math.Sin(latitude)*math.Cos(declination)*math.Cos(hourAngle)
+	return mgl32.Vec3{float32(east), float32(up), float32(-north)}.Normalize()
+}
 
-	return mgl32.Vec3{x, y, z}
+// GetDynamicLightPos — позиция солнца в мире: на sunDistance от игрока по SunDirection
+func GetDynamicLightPos(playerPos mgl32.Vec3, clock world.ClockState, Config *config.Config) mgl32.Vec3 {
+	return playerPos.Add(SunDirection(clock, Config).Mul(sunDistance))
 }
@@ -0,0 +1,149 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/world"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Отсечение пещер: обход секций чанков в ширину от секции камеры. Из секции, в которую
+// вошли через грань F, можно выйти через грань G, только если внутри неё F и G соединены
+// несплошными блоками, G не ведёт назад (против одного из уже пройденных направлений)
+// и соседняя секция попадает в пирамиду видимости. Чанки без единой достигнутой секции
+// не рисуются проходами с камеры игрока; отражения и тени их по-прежнему видят.
+
+// occlusionStats — статистика отсечения для отладочной панели
+type occlusionStats struct {
+	Enabled   bool
+	InFrustum int     // чанков с геометрией в пирамиде видимости
+	Occluded  int     // из них скрыто рельефом
+	Sections  int     // достигнуто секций
+	Millis    float64 // время обхода
+}
+
+var lastOcclusionStats occlusionStats
+
+// sectionStep — секция в очереди обхода
+type sectionStep struct {
+	pos  [3]int // чанк по X, секция по Y, чанк по Z
+	from int    // грань, через которую вошли; -1 — секция камеры
+	dirs uint8  // пройденные направления (биты граней)
+}
+
+// Буферы обхода переиспользуются между кадрами
+var (
+	occlusionVisited []bool
+	occlusionQueue   []sectionStep
+)
+
+// updateChunkOcclusion помечает чанки, скрытые от камеры в точке eye рельефом.
+// Если камера вне загруженного мира или отсечение выключено, скрытых нет.
+func updateChunkOcclusion(worldObj *world.World, eye mgl32.Vec3, frustumPlanes [6]mgl32.Vec4, Config *config.Config) {
+	start := time.Now()
+	stats := occlusionStats{Enabled: Config.OcclusionCulling}
+	defer func() {
+		stats.Millis = float64(time.Since(start).Microseconds()) / 1000
+		lastOcclusionStats = stats
+	}()
+
+	for _, gc := range gpuChunks {
+		gc.occluded = false
+	}
+	sizeX, sizeY, sizeZ := worldObj.SizeX, worldObj.SizeY, worldObj.SizeZ
+	sectionCount := (sizeY + world.SectionHeight - 1) / world.SectionHeight
+	camera := [3]int{
+		int(math.Floor(float64(eye.X()) / float64(sizeX))),
+		int(math.Floor(float64(eye.Y()) / world.SectionHeight)),
+		int(math.Floor(float64(eye.Z()) / float64(sizeZ))),
+	}
+	if !Config.OcclusionCulling || len(gpuChunks) == 0 || camera[1] < 0 || camera[1] >= sectionCount {
+		return
+	}
+
+	// Область обхода — прямоугольник загруженных чанков
+	minX, minZ, maxX, maxZ := math.MaxInt, math.MaxInt, math.MinInt, math.MinInt
+	for coord := range gpuChunks {
+		minX, maxX = min(minX, coord[0]), max(maxX, coord[0])
+		minZ, maxZ = min(minZ, coord[1]), max(maxZ, coord[1])
+	}
+	if camera[0] < minX || camera[0] > maxX || camera[2] < minZ || camera[2] > maxZ {
+		return
+	}
+	width, depth := maxX-minX+1, maxZ-minZ+1
+	index := func(p [3]int) int { return (p[0] - minX) + (p[2]-minZ)*width + p[1]*width*depth }
+	if n := width * depth * sectionCount; cap(occlusionVisited) < n {
+		occlusionVisited = make([]bool, n)
+	} else {
+		occlusionVisited = occlusionVisited[:n]
+		clear(occlusionVisited)
+	}
+
+	reached := make(map[[2]int]bool, len(gpuChunks))
+	occlusionVisited[index(camera)] = true
+	occlusionQueue = append(occlusionQueue[:0], sectionStep{pos: camera, from: -1})
+	for head := 0; head < len(occlusionQueue); head++ {
+		step := occlusionQueue[head]
+		stats.Sections++
+		reached[[2]int{step.pos[0], step.pos[2]}] = true
+
+		// Незагруженный чанк считается пустым: сквозь него видно дальше
+		var visibility world.SectionVisibility = math.MaxUint16
+		if gc, ok := gpuChunks[[2]int{step.pos[0], step.pos[2]}]; ok && step.pos[1] < len(gc.Sections) {
+			visibility = gc.Sections[step.pos[1]]
+		}
+
+		for face, off := range world.FaceOffsets {
+			if step.dirs&(1<<(face^1)) != 0 {
+				continue // назад не возвращаемся
+			}
+			if step.from >= 0 && !visibility.Connected(step.from, face) {
+				continue
+			}
+			next := [3]int{step.pos[0] + off[0], step.pos[1] + off[1], step.pos[2] + off[2]}
+			if next[0] < minX || next[0] > maxX || next[2] < minZ || next[2] > maxZ || next[1] < 0 || next[1] >= sectionCount {
+				continue
+			}
+			if occlusionVisited[index(next)] {
+				continue
+			}
+			bounds := [2]mgl32.Vec3{
+				{float32(next[0] * sizeX), float32(next[1] * world.SectionHeight), float32(next[2] * sizeZ)},
+				{float32((next[0] + 1) * sizeX), float32(min((next[1]+1)*world.SectionHeight, sizeY)), float32((next[2] + 1) * sizeZ)},
+			}
+			if !isChunkVisible(frustumPlanes, bounds) {
+				continue
+			}
+			occlusionVisited[index(next)] = true
+			occlusionQueue = append(occlusionQueue, sectionStep{pos: next, from: face ^ 1, dirs: step.dirs | 1<<face})
+		}
+	}
+
+	for coord, gc := range gpuChunks {
+		if gc.IndicesCount == 0 || !isChunkVisible(frustumPlanes, gc.Bounds) {
+			continue
+		}
+		stats.InFrustum++
+		if !reached[coord] {
+			gc.occluded = true
+			stats.Occluded++
+		}
+	}
+}
+
+// isChunkDrawn — чанк в пирамиде видимости и не скрыт рельефом (для проходов с камеры игрока)
+func isChunkDrawn(frustumPlanes [6]mgl32.Vec4, gc *gpuChunk) bool {
+	return !gc.occluded && isChunkVisible(frustumPlanes, gc.Bounds)
+}
+
+// occlusionDescription — строка отладочной панели об отсечении пещер
+func occlusionDescription() string {
+	s := lastOcclusionStats
+	if !s.Enabled {
+		return "off"
+	}
+	return fmt.Sprintf("%d of %d culled, %d sections (%.2f ms)", s.Occluded, s.InFrustum, s.Sections, s.Millis)
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"engine/src/menu"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderPauseMenu отрисовывает затемнение экрана и пункты меню паузы
+func RenderPauseMenu(window *glfw.Window, rectProgram, textProgram *ShaderProgram, m *menu.PauseMenu) {
+	width, height := window.GetSize()
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+
+	orthoProjection := mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
+
+	// Затемнение и плашки пунктов рисуем шейдером перекрестия (сплошной цвет)
+	rectProgram.Use()
+	rectProgram.SetMat4("ortho", orthoProjection)
+	renderFilledRect(rectProgram, 0, 0, float32(width), float32(height), [4]float32{0, 0, 0, 0.5})
+	for i := range m.Items {
+		r := m.ItemRect(i, width, height)
+		color := [4]float32{0.2, 0.2, 0.2, 0.8}
+		if i == m.Selected {
+			color = [4]float32{0.45, 0.45, 0.45, 0.9}
+		}
+		renderFilledRect(rectProgram, r[0], r[1], r[2], r[3], color)
+	}
+
+	// Подписи пунктов
+	textHeight := textLineHeight(hudTextSize)
+	for i, item := range m.Items {
+		r := m.ItemRect(i, width, height)
+		drawText(item, r[0]+12, r[1]+(r[3]-textHeight)/2, hudTextSize, [4]float32{1, 1, 1, 1}, 0)
+	}
+	flushText(window, textProgram)
+
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Disable(gl.BLEND)
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"engine/src/player"
+	"engine/src/profiler"
+	"engine/src/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Буферы модели игрока создаются один раз при первой отрисовке
+var (
+	playerModelVAO     uint32
+	playerModelIndices int32
+)
+
+// Части модели игрока: минимальная и максимальная точка (от ступней, лицом к +Z) и цвет
+var playerModelParts = []struct {
+	Min, Max mgl32.Vec3
+	Color    [3]float32
+}{
+	{mgl32.Vec3{-0.28, 0.0, -0.12}, mgl32.Vec3{-0.02, 0.75, 0.12}, [3]float32{0.2, 0.25, 0.6}}, // левая нога
+	{mgl32.Vec3{0.02, 0.0, -0.12}, mgl32.Vec3{0.28, 0.75, 0.12}, [3]float32{0.2, 0.25, 0.6}},   // правая нога
+	{mgl32.Vec3{-0.3, 0.75, -0.15}, mgl32.Vec3{0.3, 1.4, 0.15}, [3]float32{0.1, 0.6, 0.7}},     // туловище
+	{mgl32.Vec3{-0.45, 0.8, -0.12}, mgl32.Vec3{-0.3, 1.4, 0.12}, [3]float32{0.85, 0.65, 0.5}},  // левая рука
+	{mgl32.Vec3{0.3, 0.8, -0.12}, mgl32.Vec3{0.45, 1.4, 0.12}, [3]float32{0.85, 0.65, 0.5}},    // правая рука
+	{mgl32.Vec3{-0.22, 1.4, -0.22}, mgl32.Vec3{0.22, 1.84, 0.22}, [3]float32{0.85, 0.65, 0.5}}, // голова
+}
+
+// buildPlayerModel собирает меш модели в том же формате, что и чанки (позиция, нормаль, цвет,
+// материал, текстура); модель рисуется без текстур
+func buildPlayerModel() ([]float32, []uint32) {
+	var vertices []float32
+	var indices []uint32
+
+	for _, part := range playerModelParts {
+		size := part.Max.Sub(part.Min)
+		for _, face := range boxFaces {
+			startIdx := uint32(len(vertices) / world.VertexFloats)
+			for _, vtx := range face.Vertices {
+				vertices = append(vertices,
+					part.Min.X()+vtx[0]*size.X(), part.Min.Y()+vtx[1]*size.Y(), part.Min.Z()+vtx[2]*size.Z(),
+					face.Normal[0], face.Normal[1], face.Normal[2],
+					part.Color[0], part.Color[1], part.Color[2],
+					world.MaterialOpaque,
+					0, 0, world.NoTexture)
+			}
+			indices = append(indices,
+				startIdx+0, startIdx+1, startIdx+2,
+				startIdx+2, startIdx+3, startIdx+0)
+		}
+	}
+	return vertices, indices
+}
+
+// Грани единичного куба для модели игрока
+var boxFaces = []struct {
+	Normal   [3]float32
+	Vertices [4][3]float32
+}{
+	{[3]float32{0, 0, 1}, [4][3]float32{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1}}},
+	{[3]float32{0, 0, -1}, [4][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}},
+	{[3]float32{-1, 0, 0}, [4][3]float32{{0, 0, 0}, {0, 0, 1}, {0, 1, 1}, {0, 1, 0}}},
+	{[3]float32{1, 0, 0}, [4][3]float32{{1, 0, 0}, {1, 0, 1}, {1, 1, 1}, {1, 1, 0}}},
+	{[3]float32{0, 1, 0}, [4][3]float32{{0, 1, 0}, {1, 1, 0}, {1, 1, 1}, {0, 1, 1}}},
+	{[3]float32{0, -1, 0}, [4][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 0, 1}, {0, 0, 1}}},
+}
+
+func createPlayerModelBuffers() {
+	vertices, indices := buildPlayerModel()
+
+	var vbo, ebo uint32
+	gl.GenVertexArrays(1, &playerModelVAO)
+	gl.BindVertexArray(playerModelVAO)
+
+	gl.GenBuffers(1, &vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	gl.GenBuffers(1, &ebo)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+
+	setupChunkVertexAttribs()
+
+	playerModelIndices = int32(len(indices))
+}
+
+// renderPlayerModel рисует коробочную модель игрока текущей программой (uniform "model")
+func renderPlayerModel(program *ShaderProgram, cameraObj *player.Camera) {
+	if !program.Ready() {
+		return
+	}
+	if playerModelVAO == 0 {
+		createPlayerModelBuffers()
+	}
+
+	// Модель смотрит вдоль +Z, поворачиваем её по yaw камеры
+	feet := cameraObj.FeetPosition()
+	angle := mgl32.DegToRad(90 - float32(cameraObj.Yaw))
+	model := mgl32.Translate3D(feet.X(), feet.Y(), feet.Z()).Mul4(mgl32.HomogRotate3DY(angle))
+	program.SetMat4("model", model)
+
+	gl.BindVertexArray(playerModelVAO)
+	gl.DrawElements(gl.TRIANGLES, playerModelIndices, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	profiler.CountDraw(int(playerModelIndices) / 3)
+}
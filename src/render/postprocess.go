@@ -0,0 +1,316 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/profiler"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// postPass — проход постобработки: полноэкранный фрагментный шейдер shaders/post_<Name>.frag,
+// читающий результат предыдущего прохода (sampler2D source). Последний включённый проход
+// пишет в экран.
+type postPass struct {
+	Name     string
+	Enabled  func(Config *config.Config) bool
+	Prepare  func(source uint32, Config *config.Config) // необязательная подготовка (размытие bloom)
+	Uniforms func(program *ShaderProgram, Config *config.Config)
+
+	program *ShaderProgram
+}
+
+// postChain — цепочка постобработки по порядку. Новый проход достаточно добавить сюда:
+// RenderScene и главный цикл о составе цепочки не знают.
+var postChain = []*postPass{
+	{
+		Name:    "bloom",
+		Enabled: func(c *config.Config) bool { return c.Bloom && bloomDownProgram.Ready() && bloomUpProgram.Ready() },
+		Prepare: prepareBloom,
+		Uniforms: func(program *ShaderProgram, c *config.Config) {
+			program.SetTexture("bloom", 1, gl.TEXTURE_2D, bloomMips[0].Texture)
+			program.SetFloat("intensity", c.BloomIntensity)
+		},
+	},
+	{
+		Name:    "tonemap",
+		Enabled: func(c *config.Config) bool { return c.ToneMapping },
+		Uniforms: func(program *ShaderProgram, c *config.Config) {
+			program.SetFloat("exposure", c.Exposure)
+		},
+	},
+	{
+		Name:    "lut",
+		Enabled: func(c *config.Config) bool { return c.ColorGrading && colorLUT != 0 },
+		Uniforms: func(program *ShaderProgram, c *config.Config) {
+			program.SetTexture("lut", 1, gl.TEXTURE_3D, colorLUT)
+			program.SetFloat("lutSize", float32(colorLUTSize))
+		},
+	},
+	{
+		Name:    "vignette",
+		Enabled: func(c *config.Config) bool { return c.Vignette && c.VignetteStrength > 0 },
+		Uniforms: func(program *ShaderProgram, c *config.Config) {
+			program.SetFloat("strength", c.VignetteStrength)
+		},
+	},
+	{
+		Name:    "fxaa",
+		Enabled: func(c *config.Config) bool { return c.FXAA },
+	},
+}
+
+// copyPass выводит буфер сцены как есть, если все проходы выключены
+var copyPass = &postPass{Name: "copy"}
+
+// sceneTarget — HDR-буфер сцены: цвет RGBA16F и текстура глубины
+type sceneTarget struct {
+	colorTarget
+	Depth uint32
+}
+
+var (
+	postProcessEnabled bool
+	sceneBuffer        sceneTarget
+	postTargets        [2]colorTarget // цели проходов попеременно: один читается, в другой пишется
+
+	bloomMips         []colorTarget // уменьшающиеся вдвое уровни свечения, первый — в половину экрана
+	bloomDownProgram  *ShaderProgram
+	bloomUpProgram    *ShaderProgram
+	colorLUT          uint32 // 3D-текстура цветокоррекции; 0 — не загружена
+	colorLUTSize      int
+	lastPostPassNames []string
+)
+
+const bloomLevels = 6
+
+// CreatePostProcess создаёт HDR-буфер сцены, цели и шейдеры цепочки постобработки.
+// Без PostProcess сцена рисуется прямо в экранный буфер, как раньше.
+func CreatePostProcess(Config *config.Config) {
+	if !Config.PostProcess {
+		return
+	}
+
+	// Проход, чей шейдер не собрался, выпадает из цепочки. Без прохода копирования
+	// сцену в экран не вывести — тогда постобработка не включается вовсе.
+	for _, pass := range append(postChain, copyPass) {
+		name := "post_" + pass.Name
+		pass.program = initProgram(pass.Name+" post-process", func() (*ShaderProgram, error) {
+			return loadProgram(name, "fullscreen.vert", name+".frag")
+		})
+	}
+	bloomDownProgram = initProgram("bloom downsample", func() (*ShaderProgram, error) {
+		return loadProgram("post_bloom_down", "fullscreen.vert", "post_bloom_down.frag")
+	})
+	bloomUpProgram = initProgram("bloom upsample", func() (*ShaderProgram, error) {
+		return loadProgram("post_bloom_up", "fullscreen.vert", "post_bloom_up.frag")
+	})
+	if !copyPass.program.Ready() {
+		return
+	}
+
+	postProcessEnabled = true
+	ensureFullscreenVAO()
+
+	width, height := int32(Config.Width), int32(Config.Height)
+	sceneBuffer = sceneTarget{colorTarget: createColorTarget(width, height, gl.LINEAR)}
+	sceneBuffer.Depth = createTargetTexture(width, height, gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sceneBuffer.FBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, sceneBuffer.Depth, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("Scene framebuffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	for i := range postTargets {
+		postTargets[i] = createColorTarget(width, height, gl.LINEAR)
+	}
+	bloomMips = bloomMips[:0]
+	for i, w, h := 0, width/2, height/2; i < bloomLevels && w > 0 && h > 0; i, w, h = i+1, w/2, h/2 {
+		bloomMips = append(bloomMips, createColorTarget(w, h, gl.LINEAR))
+	}
+
+	// Без таблицы цветокоррекция просто выключается
+	if Config.ColorGrading {
+		if err := loadColorLUT(Config.ColorLUT); err != nil {
+			fmt.Println("Color grading disabled:", err)
+		}
+	}
+}
+
+// ensureFullscreenVAO создаёт пустой VAO для полноэкранного треугольника из gl_VertexID
+func ensureFullscreenVAO() {
+	if fullscreenVAO == 0 {
+		gl.GenVertexArrays(1, &fullscreenVAO)
+	}
+}
+
+// sceneFramebuffer — куда рисуется сцена: HDR-буфер при постобработке, иначе экран
+func sceneFramebuffer() uint32 {
+	if postProcessEnabled {
+		return sceneBuffer.FBO
+	}
+	return 0
+}
+
+// postProcessHDR — сцена рисуется в HDR-буфер, яркость выше 1 сохраняется
+func postProcessHDR() bool {
+	return postProcessEnabled
+}
+
+// applyPostProcess прогоняет буфер сцены через включённые проходы цепочки; последний пишет в экран
+func applyPostProcess(Config *config.Config) {
+	if !postProcessEnabled {
+		return
+	}
+	var passes []*postPass
+	lastPostPassNames = lastPostPassNames[:0]
+	for _, pass := range postChain {
+		if pass.Enabled(Config) && pass.program.Ready() {
+			passes = append(passes, pass)
+			lastPostPassNames = append(lastPostPassNames, pass.Name)
+		}
+	}
+	if len(passes) == 0 {
+		passes = []*postPass{copyPass}
+	}
+
+	// Интерфейс рисуется в экран после сцены, его глубина должна быть чистой
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.BindVertexArray(fullscreenVAO)
+
+	width, height := sceneBuffer.Width, sceneBuffer.Height
+	source := sceneBuffer.Texture
+	for i, pass := range passes {
+		if pass.Prepare != nil {
+			pass.Prepare(source, Config)
+		}
+		var target uint32
+		if i < len(passes)-1 {
+			target = postTargets[i%2].FBO
+		}
+		gl.BindFramebuffer(gl.FRAMEBUFFER, target)
+		gl.Viewport(0, 0, width, height)
+
+		pass.program.Use()
+		bindPostSource(pass.program, source, width, height, width, height)
+		if pass.Uniforms != nil {
+			pass.Uniforms(pass.program, Config)
+		}
+		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+		profiler.CountDraw(1)
+
+		if target != 0 {
+			source = postTargets[i%2].Texture
+		}
+	}
+
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+// bindPostSource привязывает входную текстуру прохода к блоку 0 и задаёт размеры входа и цели
+func bindPostSource(program *ShaderProgram, source uint32, sourceWidth, sourceHeight, targetWidth, targetHeight int32) {
+	program.SetTexture("source", 0, gl.TEXTURE_2D, source)
+	program.SetVec2("texelSize", 1/float32(sourceWidth), 1/float32(sourceHeight))
+	program.SetVec2("targetSize", float32(targetWidth), float32(targetHeight))
+}
+
+// prepareBloom строит свечение в bloomMips[0]: яркие участки с мягким порогом уменьшаются
+// по уровням, затем на обратном ходе каждый уровень размывается и прибавляется к более крупному
+func prepareBloom(source uint32, Config *config.Config) {
+	bloomDownProgram.Use()
+	bloomDownProgram.SetFloat("threshold", Config.BloomThreshold)
+	srcTexture, srcWidth, srcHeight := source, sceneBuffer.Width, sceneBuffer.Height
+	for i, mip := range bloomMips {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, mip.FBO)
+		gl.Viewport(0, 0, mip.Width, mip.Height)
+		bindPostSource(bloomDownProgram, srcTexture, srcWidth, srcHeight, mip.Width, mip.Height)
+		var prefilter int32
+		if i == 0 {
+			prefilter = 1
+		}
+		bloomDownProgram.SetInt("prefilter", prefilter)
+		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+		profiler.CountDraw(1)
+		srcTexture, srcWidth, srcHeight = mip.Texture, mip.Width, mip.Height
+	}
+
+	bloomUpProgram.Use()
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+	for i := len(bloomMips) - 1; i > 0; i-- {
+		src, dst := bloomMips[i], bloomMips[i-1]
+		gl.BindFramebuffer(gl.FRAMEBUFFER, dst.FBO)
+		gl.Viewport(0, 0, dst.Width, dst.Height)
+		bindPostSource(bloomUpProgram, src.Texture, src.Width, src.Height, dst.Width, dst.Height)
+		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+		profiler.CountDraw(1)
+	}
+	gl.Disable(gl.BLEND)
+}
+
+// loadColorLUT загружает таблицу цветокоррекции из PNG-полосы N²×N: красный растёт слева
+// направо внутри ячейки, зелёный — сверху вниз, синий — от ячейки к ячейке
+func loadColorLUT(path string) error {
+	if path == "" {
+		return fmt.Errorf("ColorLUT is not set")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	bounds := img.Bounds()
+	size := bounds.Dy()
+	if size < 2 || bounds.Dx() != size*size {
+		return fmt.Errorf("%s: expected a %d×%d strip, got %d×%d", path, size*size, size, bounds.Dx(), size)
+	}
+	data := make([]uint8, 0, size*size*size*3)
+	for b := 0; b < size; b++ {
+		for g := 0; g < size; g++ {
+			for r := 0; r < size; r++ {
+				cr, cg, cb, _ := img.At(bounds.Min.X+b*size+r, bounds.Min.Y+g).RGBA()
+				data = append(data, uint8(cr>>8), uint8(cg>>8), uint8(cb>>8))
+			}
+		}
+	}
+
+	gl.GenTextures(1, &colorLUT)
+	gl.BindTexture(gl.TEXTURE_3D, colorLUT)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage3D(gl.TEXTURE_3D, 0, gl.RGB8, int32(size), int32(size), int32(size), 0, gl.RGB, gl.UNSIGNED_BYTE, gl.Ptr(data))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.TexParameteri(gl.TEXTURE_3D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_3D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_3D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_3D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_3D, gl.TEXTURE_WRAP_R, gl.CLAMP_TO_EDGE)
+	colorLUTSize = size
+	return nil
+}
+
+// postProcessDescription — включённые проходы для отладочной панели
+func postProcessDescription() string {
+	if !postProcessEnabled {
+		return "off"
+	}
+	if len(lastPostPassNames) == 0 {
+		return "HDR only"
+	}
+	return strings.Join(lastPostPassNames, " > ")
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ShaderProgram — собранная программа шейдеров. Активные uniform’ы и атрибуты опрашиваются
+// один раз после сборки, поэтому сеттеры не ищут расположение по имени каждый кадр.
+// Неизвестное или выброшенное компилятором имя даёт -1, и GL такую запись молча пропускает.
+type ShaderProgram struct {
+	ID   uint32
+	Name string
+
+	uniforms   map[string]int32
+	attributes map[string]int32
+}
+
+// newShaderProgram оборачивает собранную программу и опрашивает её
+func newShaderProgram(name string, id uint32) *ShaderProgram {
+	p := &ShaderProgram{Name: name}
+	p.setID(id)
+	return p
+}
+
+// setID подменяет программу (после перезагрузки шейдеров), заново опрашивает uniform’ы
+// и атрибуты и привязывает общие блоки uniform к их точкам
+func (p *ShaderProgram) setID(id uint32) {
+	p.ID = id
+	p.uniforms = map[string]int32{}
+	p.attributes = map[string]int32{}
+
+	var count, maxLength int32
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(id, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+	buf := make([]uint8, maxLength+1)
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveUniform(id, uint32(i), int32(len(buf)), &length, &size, &xtype, &buf[0])
+		// Массив перечисляется как "name[0]", в сеттеры передаётся имя без индекса
+		name := strings.TrimSuffix(string(buf[:length]), "[0]")
+		// Поля блоков uniform своего расположения не имеют — они пишутся в буфер
+		if loc := gl.GetUniformLocation(id, gl.Str(name+"\x00")); loc >= 0 {
+			p.uniforms[name] = loc
+		}
+	}
+
+	gl.GetProgramiv(id, gl.ACTIVE_ATTRIBUTES, &count)
+	gl.GetProgramiv(id, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+	buf = make([]uint8, maxLength+1)
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var xtype uint32
+		gl.GetActiveAttrib(id, uint32(i), int32(len(buf)), &length, &size, &xtype, &buf[0])
+		name := string(buf[:length])
+		p.attributes[name] = gl.GetAttribLocation(id, gl.Str(name+"\x00"))
+	}
+
+	bindUniformBlocks(id)
+}
+
+// initProgram собирает программу при запуске. Если шейдер не собирается даже из встроенных
+// копий, ошибка печатается, а вместо программы возвращается пустая: её сеттеры ничего
+// не делают, а проходы с ней пропускаются (см. Ready).
+func initProgram(what string, compile func() (*ShaderProgram, error)) *ShaderProgram {
+	program, err := compile()
+	if err != nil {
+		fmt.Printf("Error compiling %s shaders, pass disabled: %v\n", what, err)
+		return &ShaderProgram{Name: what}
+	}
+	return program
+}
+
+// Ready — программа собрана; с пустой программой проход не рисуется
+func (p *ShaderProgram) Ready() bool {
+	return p.ID != 0
+}
+
+// Use делает программу текущей
+func (p *ShaderProgram) Use() {
+	gl.UseProgram(p.ID)
+}
+
+// Location — расположение активного uniform’а, -1 если его нет
+func (p *ShaderProgram) Location(name string) int32 {
+	if loc, ok := p.uniforms[name]; ok {
+		return loc
+	}
+	return -1
+}
+
+// AttribLocation — расположение активного атрибута вершин, -1 если его нет
+func (p *ShaderProgram) AttribLocation(name string) int32 {
+	if loc, ok := p.attributes[name]; ok {
+		return loc
+	}
+	return -1
+}
+
+// Сеттеры пишут в текущую программу: перед ними должна быть вызвана Use
+
+func (p *ShaderProgram) SetInt(name string, v int32) {
+	gl.Uniform1i(p.Location(name), v)
+}
+
+func (p *ShaderProgram) SetBool(name string, v bool) {
+	var flag int32
+	if v {
+		flag = 1
+	}
+	gl.Uniform1i(p.Location(name), flag)
+}
+
+func (p *ShaderProgram) SetFloat(name string, v float32) {
+	gl.Uniform1f(p.Location(name), v)
+}
+
+func (p *ShaderProgram) SetVec2(name string, x, y float32) {
+	gl.Uniform2f(p.Location(name), x, y)
+}
+
+func (p *ShaderProgram) SetIVec2(name string, x, y int32) {
+	gl.Uniform2i(p.Location(name), x, y)
+}
+
+func (p *ShaderProgram) SetVec3(name string, v mgl32.Vec3) {
+	gl.Uniform3f(p.Location(name), v.X(), v.Y(), v.Z())
+}
+
+func (p *ShaderProgram) SetVec4(name string, v mgl32.Vec4) {
+	gl.Uniform4f(p.Location(name), v.X(), v.Y(), v.Z(), v.W())
+}
+
+func (p *ShaderProgram) SetMat4(name string, m mgl32.Mat4) {
+	gl.UniformMatrix4fv(p.Location(name), 1, false, &m[0])
+}
+
+// SetVec3Array задаёт массив vec3 из подряд идущих компонент
+func (p *ShaderProgram) SetVec3Array(name string, v []float32) {
+	gl.Uniform3fv(p.Location(name), int32(len(v)/3), &v[0])
+}
+
+// SetTexture привязывает текстуру target к блоку unit и сэмплер name — к этому блоку
+func (p *ShaderProgram) SetTexture(name string, unit uint32, target, texture uint32) {
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(target, texture)
+	gl.Uniform1i(p.Location(name), int32(unit))
+}
@@ -3,6 +3,7 @@ package render
 import (
 	"engine/src/config"
 	"engine/src/player"
+	"engine/src/profiler"
 	"engine/src/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -10,96 +11,152 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
-// Параметры для отражения (planar reflection)
+// Карта теней и цели отрисовки воды
 var (
-	depthMapFBO   uint32
-	depthMap      uint32
-	reflectionFBO uint32
-	reflectionTex uint32
-	reflectWidth  = int32(1024)
-	reflectHeight = int32(1024)
-	waterLevel    = float32(0.0) // Плоскость, относительно которой зеркалим камеру
+	depthMapFBO uint32
+	depthMap    uint32
+	waterLevel  = float32(0.0) // уровень моря: плоскость отражения и отсечения (задаётся в CreateWaterTargets)
+)
+
+// Проходы отрисовки мира: основной и вспомогательные (отражение, преломление) отбрасывают
+// воду, проход полупрозрачного дорисовывает только её — поверх непрозрачного прямого
+// конвейера или отложенного освещения.
+const (
+	passMain        = 0
+	passReflection  = 1
+	passRefraction  = 2
+	passTranslucent = 3
 )
 
 func RenderScene(
 	window *glfw.Window,
-	program uint32,
+	program *ShaderProgram,
 	config *config.Config,
 	worldObj *world.World,
 	cameraObj *player.Camera,
-	lightSpaceMatrix mgl32.Mat4,
-	lightPos mgl32.Vec3,
+	sky SkyState,
 	deltaTime float64,
-	textProgram uint32,
+	textProgram *ShaderProgram,
 ) {
-	// Настраиваем вьюпорт под размер окна
-	gl.Viewport(0, 0, int32(config.Width), int32(config.Height))
-	gl.ClearColor(0.7, 0.8, 1.0, 1.0) // небо
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-
-	gl.UseProgram(program)
+	// Глаз камеры в жидкости — включаем подводный туман
+	eye := cameraObj.EyePosition()
+	underwater := worldObj.IsLiquidAt(eye.X(), eye.Y(), eye.Z())
+	waterTime += deltaTime
 
 	// Матрицы вида и проекции (с нормальной, не-зеркальной камерой)
 	view := cameraObj.GetViewMatrix()
-	projection := mgl32.Perspective(mgl32.DegToRad(60),
-		float32(config.Width)/float32(config.Height),
-		0.1, 3000.0)
-
-	setUniformMatrix4fv(program, "view", view)
-	setUniformMatrix4fv(program, "projection", projection)
-	setUniformMatrix4fv(program, "lightSpaceMatrix", lightSpaceMatrix)
-
-	// Направление света — из lightPos в сцену
-	lightDir := lightPos.Sub(cameraObj.Position).Normalize()
-	lightDirLoc := gl.GetUniformLocation(program, gl.Str("lightDir\x00"))
-	gl.Uniform3f(lightDirLoc, lightDir.X(), lightDir.Y(), lightDir.Z())
-
-	// Общие uniform’ы (lightColor, ambient, fog, и т.д.)
-	setupCommonUniforms(program, cameraObj.Position, config)
-
-	// Привязываем shadowMap
-	gl.ActiveTexture(gl.TEXTURE1)
-	gl.BindTexture(gl.TEXTURE_2D, depthMap)
-	shadowMapLoc := gl.GetUniformLocation(program, gl.Str("shadowMap\x00"))
-	gl.Uniform1i(shadowMapLoc, 1)
-
-	// Привязываем reflectionTex (на TEXTURE2)
-	gl.ActiveTexture(gl.TEXTURE2)
-	gl.BindTexture(gl.TEXTURE_2D, reflectionTex)
-	reflectionMapLoc := gl.GetUniformLocation(program, gl.Str("reflectionMap\x00"))
-	gl.Uniform1i(reflectionMapLoc, 2)
+	projection := cameraObj.GetProjectionMatrix(float32(config.Width) / float32(config.Height))
 
-	frustumPlanes := calculateFrustumPlanes(view, projection)
+	// Камера, свет и туман основного вида — для предварительного прохода SSAO и G-буфера
+	updateFrameUniforms(view, projection, eye, config, sky, underwater)
 
-	// Рендерим чанки
-	worldObj.Mu.Lock()
-	for coord, chunk := range worldObj.Chunks {
-		// Точно так же: если буфер не создан, создаём
-		if chunk.VAO == 0 && len(chunk.Vertices) > 0 && len(chunk.Indices) > 0 {
-			safeDeleteBuffers(&chunk.VAO, &chunk.VBO, &chunk.EBO)
-			createChunkBuffers(chunk)
-			Cunt_ch++
-		} else if chunk.UpdateBuf {
-			updateChunkBuffers(chunk)
-		}
+	// Чанки, скрытые рельефом, не рисуются ни одним проходом с этой камеры
+	updateChunkOcclusion(worldObj, eye, calculateFrustumPlanes(view, projection), config)
+
+	// SSAO прямого конвейера: нормали и глубина — отдельным проходом до основного
+	// (отложенный берёт их из G-буфера)
+	if ssaoActive(config) {
+		ensureSSAO(config)
+	}
+	ssaoFrame = ssaoActive(config)
+	if ssaoFrame && !deferredPipeline {
+		profiler.Begin("ssao")
+		renderAOPrepass(cameraObj, view, projection)
+		computeSSAO(ssaoPrepass.Depth, ssaoPrepass.Normal, view, projection, config)
+		profiler.End()
+	}
+
+	// Сцена рисуется в HDR-буфер постобработки (или сразу в экран, если она выключена)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sceneFramebuffer())
 
-		if !isChunkVisible(frustumPlanes, chunk.GetBoundingBox(coord)) {
-			continue
+	// Настраиваем вьюпорт под размер окна
+	gl.Viewport(0, 0, int32(config.Width), int32(config.Height))
+	gl.ClearColor(underwaterColor[0], underwaterColor[1], underwaterColor[2], 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	if deferredPipeline {
+		renderDeferred(program, config, cameraObj, view, projection, eye, sky, underwater)
+	} else {
+		profiler.Begin("opaque")
+		// Под водой небо скрыто туманом
+		if !underwater {
+			renderSky(view, projection, eye, sky, postProcessHDR())
 		}
 
-		if chunk.VAO == 0 {
-			continue
+		renderWorldPass(program, config, view, projection, eye, sky, passMain, underwater)
+
+		// Тело игрока видно только от третьего лица
+		if cameraObj.Mode == player.ThirdPerson {
+			renderPlayerModel(program, cameraObj)
 		}
+		profiler.End()
+
+		// Вода — отдельным проходом поверх непрозрачного, со своим замером
+		profiler.Begin("translucent")
+		renderWorldPass(program, config, view, projection, eye, sky, passTranslucent, underwater)
+		profiler.End()
+	}
+
+	// Дождь и снег — полупрозрачные, после непрозрачной геометрии
+	if !underwater {
+		profiler.Begin("precipitation")
+		renderPrecipitation(view, projection, eye, sky, config)
+		profiler.End()
+	}
 
-		model := mgl32.Translate3D(
-			float32(coord[0]*chunk.SizeX),
-			0,
-			float32(coord[1]*chunk.SizeZ),
-		)
-		setUniformMatrix4fv(program, "model", model)
+	// Тональная кривая, bloom, FXAA и прочие проходы — в экранный буфер
+	profiler.Begin("post")
+	applyPostProcess(config)
+	profiler.End()
 
-		gl.BindVertexArray(chunk.VAO)
-		gl.DrawElements(gl.TRIANGLES, int32(chunk.IndicesCount), gl.UNSIGNED_INT, gl.PtrOffset(0))
+	// Отладочный вид: затенение вместо сцены
+	if ssaoActive(config) && config.SSAODebug {
+		renderSSAODebug(config)
 	}
-	worldObj.Mu.Unlock()
+}
+
+// renderWorldPass рисует чанки основным шейдером в текущий framebuffer.
+// Плоскость отсечения (gl_ClipDistance[0]) включает вызывающий проход отражения или преломления.
+func renderWorldPass(
+	program *ShaderProgram,
+	config *config.Config,
+	view, projection mgl32.Mat4,
+	eye mgl32.Vec3,
+	sky SkyState,
+	pass int32,
+	underwater bool,
+) {
+	program.Use()
+
+	// Камера, свет (от солнца или луны и купола неба) и туман прохода — в общий блок FrameData
+	updateFrameUniforms(view, projection, eye, config, sky, underwater)
+
+	// Параметры блика
+	program.SetFloat("shininess", 32.0)
+	program.SetFloat("specularStrength", 0.5)
+
+	// Каскады теней
+	setupShadowUniforms(program)
+
+	// Текстуры и параметры воды
+	setupWaterUniforms(program, config, pass)
+
+	// Намокание под дождём
+	setupWeatherUniforms(program, sky)
+
+	// Затенение ambient (SSAO) посчитано только для основного вида
+	setupAOUniforms(program, pass == passMain && ssaoActive(config))
+
+	bindBlockTextures(program)
+
+	frustumPlanes := calculateFrustumPlanes(view, projection)
+
+	// Рендерим чанки (буферы загружены заранее в ProcessChunkUploads).
+	// Отражение смотрит из другой точки, поэтому отсечение пещер к нему не применяется
+	drawChunks(program, func(gc *gpuChunk) bool {
+		if pass == passReflection {
+			return isChunkVisible(frustumPlanes, gc.Bounds)
+		}
+		return isChunkDrawn(frustumPlanes, gc)
+	})
 }
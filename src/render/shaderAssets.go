@@ -0,0 +1,158 @@
+package render
+
+import (
+	"engine/src/assets"
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Исходники шейдеров лежат в ресурсах: shaders/<файл>.vert и shaders/<файл>.frag (полный
+// исходник без завершающего нуля). Базовые файлы — в AssetsDir, ресурс-пак может заменить
+// любой из них. Изменённые файлы перекомпилируются на лету; при ошибке печатается журнал
+// компиляции, а программа остаётся прежней. Копии базовых файлов встроены в бинарник и
+// берутся, только если файла нет на диске или файлы с диска не компилируются.
+//
+// Общий код шейдеров подключается строкой #include "<файл>" из shaders/include/<файл>.
+// Каждый файл подключается в исходник один раз, повторные #include пропускаются.
+
+// loadProgram собирает программу name из шейдеров vertex и fragment (имена файлов в shaders/)
+// и следит за их файлами. Если файлы с диска не компилируются, берутся встроенные копии;
+// ошибка возвращается, только если не собираются и они.
+func loadProgram(name, vertex, fragment string) (*ShaderProgram, error) {
+	vertexAsset, fragmentAsset := "shaders/"+vertex, "shaders/"+fragment
+	var includes []string
+	sources := func(fromDisk bool) (string, string, error) {
+		includes = includes[:0]
+		vs, err := shaderSource(vertexAsset, fromDisk, &includes)
+		if err != nil {
+			return "", "", err
+		}
+		fs, err := shaderSource(fragmentAsset, fromDisk, &includes)
+		return vs, fs, err
+	}
+
+	vs, fs, err := sources(true)
+	var id uint32
+	if err == nil {
+		id, err = compileProgram(vs, fs)
+	}
+	if err != nil && onDisk(append([]string{vertexAsset, fragmentAsset}, includes...)) {
+		fmt.Printf("Shader %s failed, using built-in: %v\n", name, err)
+		if vs, fs, err = sources(false); err == nil {
+			id, err = compileProgram(vs, fs)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	program := newShaderProgram(name, id)
+	var watch func()
+	watch = func() {
+		files := append([]string{vertexAsset, fragmentAsset}, includes...)
+		assets.Watch("shader:"+name, files, func() error {
+			vs, fs, err := sources(true)
+			// Набор подключаемых файлов мог измениться вместе с исходником
+			watch()
+			if err != nil {
+				return err
+			}
+			id, err := compileProgram(vs, fs)
+			if err != nil {
+				return err
+			}
+			gl.DeleteProgram(program.ID)
+			program.setID(id)
+			return nil
+		})
+	}
+	watch()
+	return program, nil
+}
+
+// onDisk — хотя бы один из файлов есть в AssetsDir или ресурс-паке
+func onDisk(names []string) bool {
+	for _, name := range names {
+		if assets.Exists(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// readShaderFile читает файл шейдера с диска (fromDisk) или его встроенную копию
+func readShaderFile(name string, fromDisk bool) (string, error) {
+	var data []byte
+	var err error
+	if fromDisk {
+		data, err = assets.Read(name)
+	} else {
+		data, err = assets.ReadBuiltin(name)
+	}
+	return string(data), err
+}
+
+// shaderSource — исходник шейдера с раскрытыми #include и завершающим нулём.
+// В includes добавляются подключённые файлы, за которыми нужно следить.
+func shaderSource(name string, fromDisk bool, includes *[]string) (string, error) {
+	src, err := readShaderFile(name, fromDisk)
+	if err != nil {
+		return "", err
+	}
+	return preprocessShader(src+"\x00", fromDisk, includes)
+}
+
+// preprocessShader раскрывает #include в исходнике. fromDisk — брать подключения с диска,
+// иначе только встроенные копии. После каждого подключения
+// в сам исходник ставится #line, чтобы номера строк в журнале компиляции совпадали с ним.
+func preprocessShader(src string, fromDisk bool, includes *[]string) (string, error) {
+	var out strings.Builder
+	if err := expandIncludes(&out, src, true, fromDisk, includes, map[string]bool{}); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func expandIncludes(out *strings.Builder, src string, top, fromDisk bool, includes *[]string, included map[string]bool) error {
+	lines := strings.SplitAfter(src, "\n")
+	for i, line := range lines {
+		directive := strings.TrimSpace(line)
+		if !strings.HasPrefix(directive, "#include") {
+			out.WriteString(line)
+			continue
+		}
+		file := strings.Trim(strings.TrimSpace(strings.TrimPrefix(directive, "#include")), `"<>`)
+		if included[file] {
+			out.WriteString("\n") // строка остаётся, чтобы не сбить нумерацию
+			continue
+		}
+		included[file] = true
+
+		body, err := includeSource(file, fromDisk, includes)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if err := expandIncludes(out, body, false, fromDisk, includes, included); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if top {
+			fmt.Fprintf(out, "\n#line %d\n", i+2)
+		}
+	}
+	return nil
+}
+
+// includeSource — текст подключаемого файла shaders/include/<file>
+func includeSource(file string, fromDisk bool, includes *[]string) (string, error) {
+	asset := "shaders/include/" + file
+	if fromDisk {
+		*includes = append(*includes, asset)
+	}
+	body, err := readShaderFile(asset, fromDisk)
+	if err != nil {
+		return "", fmt.Errorf("#include %q: %w", file, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,255 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/profiler"
+	"engine/src/world"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SkyState — состояние неба на кадр: положения светил и цвета, которыми
+// освещается и затуманивается мир. Считается в UpdateSky по той же аналитической
+// модели рассеяния, что и шейдер неба, поэтому туман совпадает с горизонтом.
+type SkyState struct {
+	SunDir  mgl32.Vec3 // направление на солнце
+	MoonDir mgl32.Vec3 // направление на луну (напротив солнца)
+
+	LightDir     mgl32.Vec3 // направление на основной источник: солнце днём, луна ночью
+	LightColor   mgl32.Vec3
+	AmbientColor mgl32.Vec3
+	FogColor     mgl32.Vec3
+
+	NightFactor float32 // 0 днём, 1 ночью: яркость звёзд
+
+	Weather  world.WeatherState // погода: облака, осадки, намокание, молнии (ApplyWeather)
+	Overcast float32            // 0..1, насколько небо затянуто сплошными облаками
+}
+
+var (
+	skyProgram *ShaderProgram
+	skyVAO     uint32
+)
+
+// Параметры атмосферы (модель однократного рассеяния Хоффмана–Притхэма)
+var (
+	skyBetaRayleigh   = [3]float64{5.8e-6, 13.5e-6, 33.1e-6} // коэффициенты рассеяния Рэлея, 1/м
+	skyBetaMie        = 21e-6                                // коэффициент рассеяния Ми, 1/м
+	skyHeightRayleigh = 8000.0                               // приведённая толщина слоя Рэлея, м
+	skyHeightMie      = 1200.0                               // приведённая толщина слоя Ми, м
+	skyMieG           = 0.76                                 // асимметрия рассеяния Ми
+	skySunIntensity   = 20.0
+	skyMoonIntensity  = 0.4
+	skyExposure       = 3.0
+
+	skyNightColor = [3]float32{0.004, 0.006, 0.015} // фон ночного неба
+	skyMoonTint   = [3]float32{0.75, 0.85, 1.0}     // оттенок лунного света и ночного ambient
+	skyDiscRadius = 0.035                           // угловой радиус дисков солнца и луны, рад
+	skySunGlow    = float32(0.5)                    // доля яркости диска солнца сверх 1 в HDR-буфере
+
+	cloudLayers    = [2]float32{600, 1100}    // высоты слоёв облаков
+	cloudScale     = float32(0.0015)          // масштаб шума облаков
+	cloudWind      = mgl32.Vec2{6, 2}         // скорость ветра облаков, блоков в секунду
+	lightningColor = mgl32.Vec3{0.8, 0.85, 1} // цвет вспышки молнии
+)
+
+// CreateSky компилирует шейдер неба. Небо рисуется одним полноэкранным треугольником
+// без вершинных буферов, поэтому VAO пустой.
+func CreateSky() {
+	skyProgram = initProgram("sky", compileSkyShader)
+	gl.GenVertexArrays(1, &skyVAO)
+}
+
+// UpdateSky вычисляет состояние неба по направлению на солнце
+// (GetDynamicLightPos минус позиция игрока). Луна — в противоположной точке неба.
+// Яркость ночи задаётся в конфиге: MoonlightIntensity и NightAmbient.
+func UpdateSky(sunDir mgl32.Vec3, Config *config.Config) SkyState {
+	sun := sunDir.Normalize()
+	sky := SkyState{SunDir: sun, MoonDir: sun.Mul(-1)}
+
+	sunFade := smoothstep(-0.02, 0.1, float64(sun.Y()))
+	moonFade := smoothstep(-0.02, 0.1, float64(sky.MoonDir.Y()))
+	sky.NightFactor = float32(1 - smoothstep(-0.15, 0.05, float64(sun.Y())))
+
+	// Цвет солнечного света — пропускание атмосферы к солнцу, нормированное к зениту:
+	// в полдень белый, на закате красный
+	noon := skyTransmittance(1)
+	trans := skyTransmittance(float64(sun.Y()))
+	for i := 0; i < 3; i++ {
+		sky.LightColor[i] = float32(trans[i] / noon[i] * sunFade)
+	}
+
+	// Ночью светит луна, если она над горизонтом
+	sky.LightDir = sun
+	if sun.Y() < 0 {
+		sky.LightDir = sky.MoonDir
+		for i := 0; i < 3; i++ {
+			sky.LightColor[i] = skyMoonTint[i] * Config.MoonlightIntensity * float32(moonFade)
+		}
+	}
+
+	// Туман — средний цвет неба чуть выше горизонта по всем азимутам
+	const azimuths = 8
+	var fog [3]float64
+	for a := 0; a < azimuths; a++ {
+		angle := 2 * math.Pi * float64(a) / azimuths
+		dir := mgl32.Vec3{float32(math.Cos(angle)), 0.05, float32(math.Sin(angle))}.Normalize()
+		c := skyColor(dir, sky)
+		for i := 0; i < 3; i++ {
+			fog[i] += float64(c[i]) / azimuths
+		}
+	}
+	zenith := skyColor(mgl32.Vec3{0, 1, 0}, sky)
+	for i := 0; i < 3; i++ {
+		sky.FogColor[i] = float32(fog[i])
+		// Рассеянный свет — от всего купола: зенит и горизонт поровну (в полдень ≈ 0.2, как раньше)
+		sky.AmbientColor[i] = skyMoonTint[i]*Config.NightAmbient + 0.12*(float32(fog[i])+zenith[i])
+	}
+	return sky
+}
+
+// ApplyWeather учитывает погоду в состоянии неба: сплошная облачность гасит прямой свет
+// и делает туман серым, вспышка молнии высвечивает рассеянный свет и туман.
+// Шейдер неба смешивает с серым по тому же Overcast, поэтому туман совпадает с небом.
+func ApplyWeather(sky SkyState, weather world.WeatherState) SkyState {
+	sky.Weather = weather
+	sky.Overcast = float32(smoothstep(0.5, 1.0, float64(weather.CloudCover)))
+
+	sky.LightColor = sky.LightColor.Mul(1 - 0.8*sky.Overcast)
+	sky.AmbientColor = sky.AmbientColor.Mul(1 - 0.3*sky.Overcast)
+	sky.FogColor = overcastColor(sky.FogColor, sky.Overcast)
+
+	sky.AmbientColor = sky.AmbientColor.Add(lightningColor.Mul(0.8 * weather.Flash))
+	sky.FogColor = sky.FogColor.Add(lightningColor.Mul(0.3 * weather.Flash))
+	return sky
+}
+
+// overcastColor смешивает цвет неба с серым той же яркости (повторяет шейдер неба)
+func overcastColor(c mgl32.Vec3, overcast float32) mgl32.Vec3 {
+	luma := 0.299*c.X() + 0.587*c.Y() + 0.114*c.Z()
+	grey := mgl32.Vec3{luma, luma, luma}.Mul(0.75)
+	return c.Add(grey.Sub(c).Mul(overcast))
+}
+
+// skyColor — цвет неба в направлении dir после тонмаппинга, без дисков и звёзд.
+// Повторяет расчёт цвета во фрагментном шейдере неба.
+func skyColor(dir mgl32.Vec3, sky SkyState) mgl32.Vec3 {
+	sunFade := smoothstep(-0.1, 0.02, float64(sky.SunDir.Y()))
+	moonFade := smoothstep(-0.1, 0.02, float64(sky.MoonDir.Y()))
+	sun := skyInscatter(dir, sky.SunDir, skySunIntensity*sunFade)
+	moon := skyInscatter(dir, sky.MoonDir, skyMoonIntensity*moonFade)
+
+	var c mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		l := float64(skyNightColor[i]) + sun[i] + moon[i]
+		c[i] = float32(1 - math.Exp(-skyExposure*l))
+	}
+	return c
+}
+
+// skyInscatter — свет источника, рассеянный атмосферой к наблюдателю вдоль dir
+func skyInscatter(dir, lightDir mgl32.Vec3, intensity float64) [3]float64 {
+	var out [3]float64
+	if intensity <= 0 {
+		return out
+	}
+	mu := float64(dir.Dot(lightDir))
+	phaseR := 3.0 / (16.0 * math.Pi) * (1 + mu*mu)
+	g := skyMieG
+	phaseM := (1 - g*g) / (4 * math.Pi * math.Pow(1+g*g-2*g*mu, 1.5))
+
+	viewMass := skyAirMass(math.Max(float64(dir.Y()), 0))
+	light := skyTransmittance(float64(lightDir.Y()))
+	for i := 0; i < 3; i++ {
+		r := skyBetaRayleigh[i] * skyHeightRayleigh
+		m := skyBetaMie * skyHeightMie
+		extinction := math.Exp(-(r + m) * viewMass)
+		out[i] = intensity * (r*phaseR + m*phaseM) / (r + m) * (1 - extinction) * light[i]
+	}
+	return out
+}
+
+// skyTransmittance — доля света, прошедшего атмосферу от источника с высотой cosZenith
+func skyTransmittance(cosZenith float64) [3]float64 {
+	mass := skyAirMass(cosZenith)
+	var t [3]float64
+	for i := 0; i < 3; i++ {
+		t[i] = math.Exp(-(skyBetaRayleigh[i]*skyHeightRayleigh + skyBetaMie*skyHeightMie) * mass)
+	}
+	return t
+}
+
+// skyAirMass — относительная оптическая масса атмосферы (формула Кастена–Янга).
+// Ниже горизонта угол ограничен, чтобы формула оставалась конечной.
+func skyAirMass(cosZenith float64) float64 {
+	zenith := math.Acos(math.Max(-1, math.Min(1, cosZenith))) * 180 / math.Pi
+	zenith = math.Min(zenith, 93.0)
+	return 1 / (math.Cos(zenith*math.Pi/180) + 0.50572*math.Pow(96.07995-zenith, -1.6364))
+}
+
+func smoothstep(edge0, edge1, x float64) float64 {
+	t := math.Max(0, math.Min(1, (x-edge0)/(edge1-edge0)))
+	return t * t * (3 - 2*t)
+}
+
+// renderSky заливает текущий framebuffer небом для матриц view/projection.
+// Рисуется первым: глубина не пишется и не проверяется. eye нужен для параллакса облаков.
+// hdr — небо рисуется в HDR-буфер сцены: диск солнца получает избыток яркости для bloom
+func renderSky(view, projection mgl32.Mat4, eye mgl32.Vec3, sky SkyState, hdr bool) {
+	if !skyProgram.Ready() {
+		return
+	}
+	// Направление луча не зависит от положения камеры — берём только поворот
+	rotation := view.Mat3().Mat4()
+	invViewProj := projection.Mul4(rotation).Inv()
+
+	skyProgram.Use()
+	skyProgram.SetMat4("invViewProj", invViewProj)
+	skyProgram.SetVec3("sunDir", sky.SunDir)
+	skyProgram.SetVec3("moonDir", sky.MoonDir)
+	skyProgram.SetFloat("nightFactor", sky.NightFactor)
+	skyProgram.SetFloat("time", float32(waterTime))
+
+	skyProgram.SetVec3("betaRayleigh", mgl32.Vec3{
+		float32(skyBetaRayleigh[0] * skyHeightRayleigh), float32(skyBetaRayleigh[1] * skyHeightRayleigh), float32(skyBetaRayleigh[2] * skyHeightRayleigh)})
+	skyProgram.SetFloat("betaMie", float32(skyBetaMie*skyHeightMie))
+	skyProgram.SetFloat("mieG", float32(skyMieG))
+	skyProgram.SetFloat("sunIntensity", float32(skySunIntensity))
+	skyProgram.SetFloat("moonIntensity", float32(skyMoonIntensity))
+	skyProgram.SetFloat("exposure", float32(skyExposure))
+	skyProgram.SetVec3("nightColor", mgl32.Vec3(skyNightColor))
+	skyProgram.SetFloat("discCos", float32(math.Cos(skyDiscRadius)))
+
+	// Облака освещены тем же светом, что и мир
+	cloudLight := sky.AmbientColor.Mul(1.5).Add(sky.LightColor.Mul(0.8))
+	skyProgram.SetVec3("cameraPos", eye)
+	skyProgram.SetVec3("cloudLight", cloudLight)
+	skyProgram.SetVec2("cloudLayers", cloudLayers[0], cloudLayers[1])
+	skyProgram.SetFloat("cloudScale", cloudScale)
+	skyProgram.SetVec2("cloudWind", cloudWind.X(), cloudWind.Y())
+	skyProgram.SetFloat("cloudCover", sky.Weather.CloudCover)
+	skyProgram.SetFloat("overcast", sky.Overcast)
+	skyProgram.SetFloat("flash", sky.Weather.Flash)
+	skyProgram.SetVec3("lightningColor", lightningColor)
+	var hdrFlag int32
+	if hdr {
+		hdrFlag = 1
+	}
+	skyProgram.SetInt("hdrOutput", hdrFlag)
+	skyProgram.SetFloat("sunGlow", skySunGlow)
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.BindVertexArray(skyVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	profiler.CountDraw(1)
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+func compileSkyShader() (*ShaderProgram, error) {
+	return loadProgram("sky", "sky.vert", "sky.frag")
+}
@@ -0,0 +1,260 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/player"
+	"engine/src/profiler"
+	"fmt"
+	"math/rand"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Затенение окружающего света в экранном пространстве (SSAO): по глубине и нормалям
+// кадра для каждого пикселя считается доля полусферы над поверхностью, закрытая соседней
+// геометрией. Результат размывается с учётом глубины и умножается на ambient.
+// В прямом конвейере нормали и глубину даёт предварительный проход, в отложенном — G-буфер.
+
+// aoPrepass — нормали и глубина предварительного прохода прямого конвейера
+type aoPrepass struct {
+	FBO           uint32
+	Normal        uint32
+	Depth         uint32
+	Width, Height int32
+}
+
+var (
+	ssaoCreated bool
+	ssaoBroken  bool // шейдеры SSAO не собрались, затенение выключено до перезапуска
+	ssaoHalf    bool // цели созданы в половинном разрешении
+	ssaoPrepass aoPrepass
+	ssaoRaw     colorTarget // затенение до размытия
+	ssaoBlurred [2]colorTarget
+	ssaoResult  uint32 // текстура итогового затенения последнего кадра
+	ssaoFrame   bool   // затенение считалось в последнем кадре
+	ssaoSamples int    // выборок на пиксель в последнем кадре
+	ssaoNoise   uint32
+	ssaoKernel  []float32
+
+	aoPrepassProgram *ShaderProgram
+	ssaoProgram      *ShaderProgram
+	ssaoBlurProgram  *ShaderProgram
+	ssaoDebugProgram *ShaderProgram
+)
+
+// Параметры SSAO
+const (
+	ssaoMaxSamples = 64
+	ssaoNoiseSize  = 4 // сторона текстуры случайных поворотов ядра
+	aoUnit         = 11
+)
+
+// ssaoActive — затенение включено в конфиге и его шейдеры собрались
+func ssaoActive(Config *config.Config) bool {
+	return Config.SSAO && !ssaoBroken
+}
+
+// ssaoDescription — состояние SSAO для отладочной панели
+func ssaoDescription() string {
+	if !ssaoFrame {
+		return "off"
+	}
+	resolution := "full"
+	if ssaoHalf {
+		resolution = "half"
+	}
+	return fmt.Sprintf("%d samples, %s resolution (%dx%d)", ssaoSamples, resolution, ssaoRaw.Width, ssaoRaw.Height)
+}
+
+// ensureSSAO создаёт цели и шейдеры SSAO при первом включении и пересоздаёт цели,
+// если сменилось разрешение (SSAOHalfResolution). Если шейдеры не собрались, SSAO выключается.
+func ensureSSAO(Config *config.Config) {
+	if ssaoCreated && ssaoHalf == Config.SSAOHalfResolution {
+		return
+	}
+	if !ssaoCreated {
+		if err := compileSSAOShaders(); err != nil {
+			fmt.Println("Error compiling SSAO shaders, SSAO disabled:", err)
+			ssaoBroken = true
+			return
+		}
+		createSSAONoise()
+		ensureFullscreenVAO()
+	} else {
+		deleteSSAOTargets()
+	}
+	ssaoCreated = true
+	ssaoHalf = Config.SSAOHalfResolution
+
+	width, height := int32(Config.Width), int32(Config.Height)
+	if ssaoHalf {
+		width, height = width/2, height/2
+	}
+	ssaoPrepass = createAOPrepass(width, height)
+	ssaoRaw = createColorTarget(width, height, gl.LINEAR)
+	for i := range ssaoBlurred {
+		ssaoBlurred[i] = createColorTarget(width, height, gl.LINEAR)
+	}
+}
+
+func compileSSAOShaders() error {
+	var err error
+	if aoPrepassProgram, err = loadProgram("ssao_prepass", "geometry.vert", "ssao_prepass.frag"); err != nil {
+		return fmt.Errorf("prepass: %w", err)
+	}
+	if ssaoProgram, err = loadProgram("ssao", "fullscreen.vert", "ssao.frag"); err != nil {
+		return err
+	}
+	if ssaoBlurProgram, err = loadProgram("ssao_blur", "fullscreen.vert", "ssao_blur.frag"); err != nil {
+		return fmt.Errorf("blur: %w", err)
+	}
+	if ssaoDebugProgram, err = loadProgram("ssao_debug", "fullscreen.vert", "ssao_debug.frag"); err != nil {
+		return fmt.Errorf("debug: %w", err)
+	}
+	return nil
+}
+
+func createAOPrepass(width, height int32) aoPrepass {
+	p := aoPrepass{Width: width, Height: height}
+	gl.GenFramebuffers(1, &p.FBO)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, p.FBO)
+	p.Normal = createTargetTexture(width, height, gl.RGBA16F, gl.RGBA, gl.FLOAT)
+	p.Depth = createTargetTexture(width, height, gl.DEPTH_COMPONENT24, gl.DEPTH_COMPONENT, gl.FLOAT)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, p.Normal, 0)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, p.Depth, 0)
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("SSAO prepass framebuffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return p
+}
+
+func deleteSSAOTargets() {
+	framebuffers := []uint32{ssaoPrepass.FBO, ssaoRaw.FBO, ssaoBlurred[0].FBO, ssaoBlurred[1].FBO}
+	textures := []uint32{ssaoPrepass.Normal, ssaoPrepass.Depth, ssaoRaw.Texture, ssaoBlurred[0].Texture, ssaoBlurred[1].Texture}
+	gl.DeleteFramebuffers(int32(len(framebuffers)), &framebuffers[0])
+	gl.DeleteTextures(int32(len(textures)), &textures[0])
+}
+
+// createSSAONoise строит ядро выборок в полусфере (гуще у центра) и текстуру
+// случайных поворотов ядра вокруг нормали, повторяющуюся по экрану
+func createSSAONoise() {
+	rng := rand.New(rand.NewSource(1))
+	ssaoKernel = make([]float32, 0, ssaoMaxSamples*3)
+	for i := 0; i < ssaoMaxSamples; i++ {
+		sample := mgl32.Vec3{
+			rng.Float32()*2 - 1,
+			rng.Float32()*2 - 1,
+			rng.Float32(),
+		}.Normalize().Mul(rng.Float32())
+		t := float32(i) / ssaoMaxSamples
+		sample = sample.Mul(0.1 + 0.9*t*t)
+		ssaoKernel = append(ssaoKernel, sample.X(), sample.Y(), sample.Z())
+	}
+
+	noise := make([]float32, 0, ssaoNoiseSize*ssaoNoiseSize*3)
+	for i := 0; i < ssaoNoiseSize*ssaoNoiseSize; i++ {
+		noise = append(noise, rng.Float32()*2-1, rng.Float32()*2-1, 0)
+	}
+	gl.GenTextures(1, &ssaoNoise)
+	gl.BindTexture(gl.TEXTURE_2D, ssaoNoise)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB16F, ssaoNoiseSize, ssaoNoiseSize, 0, gl.RGB, gl.FLOAT, gl.Ptr(noise))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+}
+
+// renderAOPrepass рисует нормали и глубину непрозрачного мира для SSAO прямого конвейера
+func renderAOPrepass(cameraObj *player.Camera, view, projection mgl32.Mat4) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, ssaoPrepass.FBO)
+	gl.Viewport(0, 0, ssaoPrepass.Width, ssaoPrepass.Height)
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	aoPrepassProgram.Use() // камера — в блоке FrameData
+	frustumPlanes := calculateFrustumPlanes(view, projection)
+	drawChunks(aoPrepassProgram, func(gc *gpuChunk) bool {
+		return isChunkDrawn(frustumPlanes, gc)
+	})
+	if cameraObj.Mode == player.ThirdPerson {
+		renderPlayerModel(aoPrepassProgram, cameraObj)
+	}
+}
+
+// computeSSAO считает затенение по текстурам глубины и мировых нормалей кадра
+// и размывает его по горизонтали и вертикали с учётом глубины (ssaoResult)
+func computeSSAO(depthTexture, normalTexture uint32, view, projection mgl32.Mat4, Config *config.Config) {
+	width, height := ssaoRaw.Width, ssaoRaw.Height
+	samples := Config.SSAOSamples
+	if samples < 1 || samples > ssaoMaxSamples {
+		samples = ssaoMaxSamples
+	}
+	ssaoSamples = samples
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+	gl.BindVertexArray(fullscreenVAO)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, ssaoRaw.FBO)
+	gl.Viewport(0, 0, width, height)
+	ssaoProgram.Use()
+	bindPostSource(ssaoProgram, depthTexture, width, height, width, height)
+	ssaoProgram.SetTexture("normalMap", 1, gl.TEXTURE_2D, normalTexture)
+	ssaoProgram.SetTexture("noiseMap", 2, gl.TEXTURE_2D, ssaoNoise)
+	ssaoProgram.SetVec2("noiseScale", float32(width)/ssaoNoiseSize, float32(height)/ssaoNoiseSize)
+	ssaoProgram.SetMat4("view", view)
+	ssaoProgram.SetMat4("projection", projection)
+	ssaoProgram.SetMat4("invProjection", projection.Inv())
+	ssaoProgram.SetVec3Array("kernel", ssaoKernel)
+	ssaoProgram.SetInt("kernelSize", int32(samples))
+	ssaoProgram.SetFloat("radius", Config.SSAORadius)
+	ssaoProgram.SetFloat("intensity", Config.SSAOIntensity)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	profiler.CountDraw(1)
+
+	// Двусторонний фильтр: соседи на другой глубине (за краем объекта) не смешиваются
+	ssaoBlurProgram.Use()
+	ssaoBlurProgram.SetTexture("depthMap", 1, gl.TEXTURE_2D, depthTexture)
+	ssaoBlurProgram.SetFloat("near", Config.NearPlane)
+	ssaoBlurProgram.SetFloat("far", Config.FarPlane)
+	source := ssaoRaw.Texture
+	for i, direction := range [2][2]float32{{1, 0}, {0, 1}} {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, ssaoBlurred[i].FBO)
+		bindPostSource(ssaoBlurProgram, source, width, height, width, height)
+		ssaoBlurProgram.SetVec2("direction", direction[0], direction[1])
+		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+		profiler.CountDraw(1)
+		source = ssaoBlurred[i].Texture
+	}
+	ssaoResult = source
+
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+// setupAOUniforms привязывает затенение к программе освещения; enabled == false — ambient как раньше
+func setupAOUniforms(program *ShaderProgram, enabled bool) {
+	var flag int32
+	if enabled && ssaoResult != 0 {
+		flag = 1
+	}
+	program.SetInt("aoEnabled", flag)
+	program.SetTexture("aoMap", aoUnit, gl.TEXTURE_2D, ssaoResult)
+}
+
+// renderSSAODebug выводит затенение на весь экран оттенками серого
+func renderSSAODebug(Config *config.Config) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(0, 0, int32(Config.Width), int32(Config.Height))
+	gl.Disable(gl.DEPTH_TEST)
+	ssaoDebugProgram.Use()
+	bindPostSource(ssaoDebugProgram, ssaoResult, ssaoRaw.Width, ssaoRaw.Height, int32(Config.Width), int32(Config.Height))
+	gl.BindVertexArray(fullscreenVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	profiler.CountDraw(1)
+	gl.BindVertexArray(0)
+	gl.Enable(gl.DEPTH_TEST)
+}
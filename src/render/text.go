@@ -0,0 +1,468 @@
+package render
+
+import (
+	"engine/src/assets"
+	"engine/src/config"
+	"engine/src/profiler"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Текст HUD: глифы растеризуются один раз в атлас (текстура R8) на каждый кегль, строки
+// раскладываются с кернингом и переносами, а четырёхугольники всех строк панели копятся
+// в одном динамическом буфере и рисуются одним вызовом (drawText, затем flushText).
+// С TextSDF атлас один на все кегли — поле расстояний, из которого края остаются чёткими
+// при любом масштабе. Глифы, которых нет в атласе, дорисовываются в него при первой встрече.
+
+// Шрифт текста HUD из ресурсов (в базовых ресурсах — Go Regular)
+const hudFontAsset = "fonts/hud.ttf"
+
+// Кегль текста HUD в пикселях
+const hudTextSize = 15
+
+const (
+	atlasWidth      = 1024
+	atlasInitHeight = 256
+	atlasMaxHeight  = 4096 // атлас растёт удвоением высоты
+	sdfBaseSize     = 48   // кегль, в котором строится поле расстояний
+	sdfSpread       = 6    // ширина поля по обе стороны контура в пикселях атласа
+)
+
+// Символы, которые кладутся в атлас сразу: ASCII, кириллица и типографские знаки
+var preloadRunes = func() []rune {
+	var runes []rune
+	for r := rune(0x20); r < 0x7F; r++ {
+		runes = append(runes, r)
+	}
+	for r := rune(0x400); r < 0x460; r++ {
+		runes = append(runes, r)
+	}
+	return append(runes, []rune("«»—–…°№·")...)
+}()
+
+// glyph — глиф в атласе: прямоугольник относительно пера на базовой линии и место в атласе
+type glyph struct {
+	x0, y0, x1, y1 float32 // в пикселях кегля атласа, y вниз
+	ax, ay         float32 // левый верхний угол в атласе
+	advance        float32
+}
+
+// fontAtlas — глифы одного кегля (или поле расстояний) в текстуре
+type fontAtlas struct {
+	face       font.Face
+	size       float32
+	sdf        bool
+	ascent     float32
+	lineHeight float32
+
+	glyphs           map[rune]*glyph
+	pixels           []uint8 // atlasWidth × height, один канал
+	height           int
+	penX, penY, rowH int
+	texture          uint32
+	uploadedHeight   int
+	dirty            bool
+}
+
+// textBatch — вершины строк одного атласа, ожидающие flushText
+type textBatch struct {
+	atlas    *fontAtlas
+	vertices []float32 // x, y, u, v (пиксели атласа), r, g, b, a
+}
+
+const textVertexFloats = 8
+
+var (
+	hudFont     *truetype.Font
+	textSDF     bool
+	textAtlases = map[float32]*fontAtlas{}
+	textQueue   []textBatch
+
+	textVAO, textVBO uint32
+)
+
+// CreateHUDFont загружает шрифт HUD и следит за его файлом
+func CreateHUDFont(Config *config.Config) {
+	textSDF = Config.TextSDF
+	if err := loadHUDFont(); err != nil {
+		fmt.Println("HUD font:", err)
+	}
+	assets.Watch("fonts/hud", []string{hudFontAsset}, loadHUDFont)
+}
+
+// loadHUDFont заменяет шрифт, только если новый файл разобрался; атласы строятся заново
+func loadHUDFont() error {
+	data, err := assets.Read(hudFontAsset)
+	if err != nil {
+		return err
+	}
+	parsed, err := truetype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", hudFontAsset, err)
+	}
+	hudFont = parsed
+	for size, atlas := range textAtlases {
+		gl.DeleteTextures(1, &atlas.texture)
+		delete(textAtlases, size)
+	}
+	return nil
+}
+
+// atlasFor возвращает атлас для кегля size, строя его при первом обращении
+func atlasFor(size float32) *fontAtlas {
+	if textSDF {
+		size = sdfBaseSize
+	}
+	if atlas, ok := textAtlases[size]; ok {
+		return atlas
+	}
+	if hudFont == nil {
+		// Файл шрифта не разобрался: встроенная копия
+		data, _ := assets.ReadBuiltin(hudFontAsset)
+		hudFont, _ = truetype.Parse(data)
+	}
+	hinting := font.HintingFull
+	if textSDF {
+		hinting = font.HintingNone // поле масштабируется, подгонка под пиксели ему не нужна
+	}
+	face := truetype.NewFace(hudFont, &truetype.Options{Size: float64(size), DPI: 72, Hinting: hinting})
+	metrics := face.Metrics()
+	atlas := &fontAtlas{
+		face:       face,
+		size:       size,
+		sdf:        textSDF,
+		ascent:     fixedToFloat(metrics.Ascent),
+		lineHeight: fixedToFloat(metrics.Height),
+		glyphs:     map[rune]*glyph{},
+		pixels:     make([]uint8, atlasWidth*atlasInitHeight),
+		height:     atlasInitHeight,
+	}
+	for _, r := range preloadRunes {
+		atlas.glyph(r)
+	}
+	textAtlases[size] = atlas
+	return atlas
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// glyph возвращает глиф руны, растеризуя его в атлас при первой встрече
+func (a *fontAtlas) glyph(r rune) *glyph {
+	if g, ok := a.glyphs[r]; ok {
+		return g
+	}
+	g := &glyph{}
+	a.glyphs[r] = g
+
+	dr, mask, maskp, advance, ok := a.face.Glyph(fixed.Point26_6{}, r)
+	if !ok {
+		return g
+	}
+	g.advance = fixedToFloat(advance)
+	if dr.Empty() {
+		return g // пробел и прочие невидимые
+	}
+
+	pad := 1 // пустая рамка, чтобы линейная фильтрация не цепляла соседей
+	if a.sdf {
+		pad = sdfSpread
+	}
+	w, h := dr.Dx()+2*pad, dr.Dy()+2*pad
+	if a.penX+w > atlasWidth {
+		a.penX, a.penY, a.rowH = 0, a.penY+a.rowH, 0
+	}
+	for a.penY+h > a.height {
+		if a.height*2 > atlasMaxHeight {
+			return g // атлас заполнен: глиф останется пустым
+		}
+		a.pixels = append(a.pixels, make([]uint8, atlasWidth*a.height)...)
+		a.height *= 2
+	}
+
+	coverage := make([]float64, w*h)
+	for y := 0; y < dr.Dy(); y++ {
+		for x := 0; x < dr.Dx(); x++ {
+			_, _, _, alpha := mask.At(maskp.X+x, maskp.Y+y).RGBA()
+			coverage[(y+pad)*w+x+pad] = float64(alpha) / 0xFFFF
+		}
+	}
+	if a.sdf {
+		coverage = signedDistanceField(coverage, w, h)
+	}
+	for y := 0; y < h; y++ {
+		row := a.pixels[(a.penY+y)*atlasWidth+a.penX:]
+		for x := 0; x < w; x++ {
+			row[x] = uint8(coverage[y*w+x]*255 + 0.5)
+		}
+	}
+
+	g.x0, g.y0 = float32(dr.Min.X-pad), float32(dr.Min.Y-pad)
+	g.x1, g.y1 = g.x0+float32(w), g.y0+float32(h)
+	g.ax, g.ay = float32(a.penX), float32(a.penY)
+	a.penX += w
+	a.rowH = max(a.rowH, h)
+	a.dirty = true
+	return g
+}
+
+// upload переносит изменившийся атлас в текстуру
+func (a *fontAtlas) upload() {
+	if a.texture == 0 {
+		gl.GenTextures(1, &a.texture)
+		gl.BindTexture(gl.TEXTURE_2D, a.texture)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		a.dirty = true
+	}
+	if !a.dirty {
+		return
+	}
+	gl.BindTexture(gl.TEXTURE_2D, a.texture)
+	if a.uploadedHeight != a.height {
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R8, atlasWidth, int32(a.height), 0, gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(a.pixels))
+		a.uploadedHeight = a.height
+	} else {
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, atlasWidth, int32(a.height), gl.RED, gl.UNSIGNED_BYTE, gl.Ptr(a.pixels))
+	}
+	a.dirty = false
+}
+
+// textLineHeight — расстояние между базовыми линиями строк кегля size
+func textLineHeight(size float32) float32 {
+	atlas := atlasFor(size)
+	return atlas.lineHeight * size / atlas.size
+}
+
+// lineWidth — ширина строки с учётом кернинга
+func lineWidth(atlas *fontAtlas, line string, size float32) float32 {
+	scale := size / atlas.size
+	width := float32(0)
+	prev := rune(-1)
+	for _, r := range line {
+		if prev >= 0 {
+			width += fixedToFloat(atlas.face.Kern(prev, r)) * scale
+		}
+		width += atlas.glyph(r).advance * scale
+		prev = r
+	}
+	return width
+}
+
+// wrapLines делит текст на строки по \n и, если maxWidth > 0, по словам на этой ширине
+func wrapLines(atlas *fontAtlas, text string, size, maxWidth float32) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if maxWidth <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+		line := ""
+		for _, word := range strings.Split(paragraph, " ") {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && lineWidth(atlas, candidate, size) > maxWidth {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawText ставит текст в очередь отрисовки: (x, y) — левый верхний угол первой строки,
+// \n начинает новую строку, maxWidth > 0 включает перенос по словам
+func drawText(text string, x, y, size float32, color [4]float32, maxWidth float32) {
+	atlas := atlasFor(size)
+	scale := size / atlas.size
+
+	var batch *textBatch
+	for i := range textQueue {
+		if textQueue[i].atlas == atlas {
+			batch = &textQueue[i]
+		}
+	}
+	if batch == nil {
+		textQueue = append(textQueue, textBatch{atlas: atlas})
+		batch = &textQueue[len(textQueue)-1]
+	}
+
+	baseline := y + atlas.ascent*scale
+	for _, line := range wrapLines(atlas, text, size, maxWidth) {
+		pen := x
+		prev := rune(-1)
+		for _, r := range line {
+			if prev >= 0 {
+				pen += fixedToFloat(atlas.face.Kern(prev, r)) * scale
+			}
+			prev = r
+			g := atlas.glyph(r)
+			if g.x1 > g.x0 {
+				// Без поля расстояний глифы ставятся в целые пиксели — иначе они размываются
+				ox, oy := pen, baseline
+				if !atlas.sdf {
+					ox, oy = float32(math.Round(float64(ox))), float32(math.Round(float64(oy)))
+				}
+				batch.vertices = appendGlyphQuad(batch.vertices, g,
+					ox+g.x0*scale, oy+g.y0*scale, ox+g.x1*scale, oy+g.y1*scale, color)
+			}
+			pen += g.advance * scale
+		}
+		baseline += atlas.lineHeight * scale
+	}
+}
+
+// appendGlyphQuad добавляет два треугольника глифа с экранными углами (x0, y0)–(x1, y1)
+func appendGlyphQuad(vertices []float32, g *glyph, x0, y0, x1, y1 float32, color [4]float32) []float32 {
+	u0, v0 := g.ax, g.ay
+	u1, v1 := g.ax+(g.x1-g.x0), g.ay+(g.y1-g.y0)
+	r, gr, b, a := color[0], color[1], color[2], color[3]
+	return append(vertices,
+		x0, y0, u0, v0, r, gr, b, a,
+		x1, y0, u1, v0, r, gr, b, a,
+		x1, y1, u1, v1, r, gr, b, a,
+		x1, y1, u1, v1, r, gr, b, a,
+		x0, y1, u0, v1, r, gr, b, a,
+		x0, y0, u0, v0, r, gr, b, a,
+	)
+}
+
+// flushText рисует накопленный drawText текст поверх экрана: один вызов на атлас.
+// Смешивание и тест глубины настраивает вызывающий.
+func flushText(window *glfw.Window, program *ShaderProgram) {
+	if len(textQueue) == 0 {
+		return
+	}
+	// Очередь переиспользует память вершин в следующих кадрах
+	defer func() {
+		for i := range textQueue {
+			textQueue[i].vertices = textQueue[i].vertices[:0]
+		}
+	}()
+	if !program.Ready() {
+		return
+	}
+	width, height := window.GetSize()
+	if textVAO == 0 {
+		gl.GenVertexArrays(1, &textVAO)
+		gl.GenBuffers(1, &textVBO)
+		gl.BindVertexArray(textVAO)
+		gl.BindBuffer(gl.ARRAY_BUFFER, textVBO)
+		stride := int32(textVertexFloats * 4)
+		gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))   // позиция
+		gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, gl.PtrOffset(2*4)) // координаты в атласе
+		gl.VertexAttribPointer(2, 4, gl.FLOAT, false, stride, gl.PtrOffset(4*4)) // цвет
+		gl.EnableVertexAttribArray(0)
+		gl.EnableVertexAttribArray(1)
+		gl.EnableVertexAttribArray(2)
+	}
+
+	program.Use()
+	program.SetMat4("ortho", mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1))
+	gl.BindVertexArray(textVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, textVBO)
+	for _, batch := range textQueue {
+		if len(batch.vertices) == 0 {
+			continue
+		}
+		batch.atlas.upload()
+		program.SetTexture("textTexture", 0, gl.TEXTURE_2D, batch.atlas.texture)
+		program.SetBool("sdf", batch.atlas.sdf)
+		// Буфер «осиротеваем»: драйвер не ждёт, пока GPU дочитает прошлую партию
+		gl.BufferData(gl.ARRAY_BUFFER, len(batch.vertices)*4, gl.Ptr(batch.vertices), gl.STREAM_DRAW)
+		count := len(batch.vertices) / textVertexFloats
+		gl.DrawArrays(gl.TRIANGLES, 0, int32(count))
+		profiler.CountDraw(count / 3)
+	}
+	gl.BindVertexArray(0)
+}
+
+// signedDistanceField переводит покрытие глифа w×h в поле расстояний до контура:
+// 0.5 — контур, больше — внутри; sdfSpread пикселей соответствуют половине диапазона
+func signedDistanceField(coverage []float64, w, h int) []float64 {
+	inside := make([]float64, w*h)  // квадрат расстояния до ближайшего пикселя внутри
+	outside := make([]float64, w*h) // и снаружи
+	for i, c := range coverage {
+		if c >= 0.5 {
+			outside[i] = edtInfinity
+		} else {
+			inside[i] = edtInfinity
+		}
+	}
+	distanceTransform(inside, w, h)
+	distanceTransform(outside, w, h)
+
+	field := make([]float64, w*h)
+	for i := range field {
+		dist := math.Sqrt(inside[i]) - math.Sqrt(outside[i]) // > 0 снаружи
+		field[i] = min(max(0.5-dist/(2*sdfSpread), 0), 1)
+	}
+	return field
+}
+
+const edtInfinity = 1e20
+
+// distanceTransform — точное евклидово преобразование расстояний (Felzenszwalb, Huttenlocher):
+// на входе 0 в пикселях-источниках и edtInfinity в остальных, на выходе квадраты расстояний
+func distanceTransform(grid []float64, w, h int) {
+	n := max(w, h)
+	f, d := make([]float64, n), make([]float64, n)
+	v, z := make([]int, n), make([]float64, n+1)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = grid[y*w+x]
+		}
+		distanceTransform1D(f[:h], d[:h], v, z)
+		for y := 0; y < h; y++ {
+			grid[y*w+x] = d[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		copy(f[:w], grid[y*w:(y+1)*w])
+		distanceTransform1D(f[:w], d[:w], v, z)
+		copy(grid[y*w:(y+1)*w], d[:w])
+	}
+}
+
+// distanceTransform1D — нижняя огибающая парабол над строкой f
+func distanceTransform1D(f, d []float64, v []int, z []float64) {
+	k := 0
+	v[0] = 0
+	z[0], z[1] = -edtInfinity, edtInfinity
+	for q := 1; q < len(f); q++ {
+		s := intersection(f, q, v[k])
+		for s <= z[k] {
+			k--
+			s = intersection(f, q, v[k])
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, edtInfinity
+	}
+	k = 0
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
+
+func intersection(f []float64, q, p int) float64 {
+	return ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*q-2*p)
+}
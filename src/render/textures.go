@@ -0,0 +1,159 @@
+package render
+
+import (
+	"bytes"
+	"engine/src/assets"
+	"engine/src/world"
+	"fmt"
+	"image"
+	_ "image/png"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Текстуры блоков: массив 2D-текстур, слой на каждое имя из world.TextureNames().
+// У каждой текстуры свой слой, поэтому соседние текстуры не просачиваются друг в друга
+// ни при повторении (REPEAT), ни на уменьшенных mip-уровнях — отступы, как в атласе, не нужны.
+
+var (
+	blockTextureArray uint32
+	blockTextureSize  int // сторона текстуры в пикселях (по первой загруженной)
+)
+
+// Блок, на котором шейдеры освещения читают текстуры блоков
+const blockTextureUnit = 12
+
+// Размер текстуры, если в ресурсах не нашлось ни одной
+const defaultBlockTextureSize = 16
+
+// Описания блоков в ресурсах: текстуры граней и блоки, доступные игроку
+const blockDefinitionsAsset = "blocks.json"
+
+// CreateBlockTextures загружает описания блоков и текстуры textures/blocks/<имя>.png и следит
+// за ними: после правки описаний меши всех чанков перестраиваются. Отсутствующая или
+// повреждённая при запуске текстура заменяется шахматкой и не останавливает игру.
+func CreateBlockTextures(worldObj *world.World) {
+	if err := loadBlockDefinitions(); err != nil {
+		fmt.Println("Block definitions:", err)
+	}
+	gl.GenTextures(1, &blockTextureArray)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, blockTextureArray)
+	// Вблизи — чёткие пиксели, вдали — сглаженные mip-уровни без мерцания
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_S, gl.REPEAT)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_T, gl.REPEAT)
+	loadBlockTextures(false)
+
+	assets.Watch("blocks", []string{blockDefinitionsAsset}, func() error {
+		if err := loadBlockDefinitions(); err != nil {
+			return err
+		}
+		// Набор текстур мог измениться: недостающие заменяются шахматкой
+		loadBlockTextures(false)
+		worldObj.RemeshAll()
+		return nil
+	})
+}
+
+func loadBlockDefinitions() error {
+	data, err := assets.Read(blockDefinitionsAsset)
+	if err != nil {
+		return err
+	}
+	return world.LoadBlockDefinitions(data)
+}
+
+// loadBlockTextures собирает массив текстур блоков и следит за их файлами. strict — при ошибке
+// чтения не менять массив (перезагрузка), иначе ошибочная текстура заменяется шахматкой.
+func loadBlockTextures(strict bool) error {
+	names := world.TextureNames()
+	paths := make([]string, len(names))
+	images := make([]image.Image, len(names))
+	size := 0
+	for i, name := range names {
+		paths[i] = "textures/blocks/" + name + ".png"
+		img, err := loadImage(paths[i])
+		if err != nil {
+			if strict {
+				return err
+			}
+			fmt.Println("Block texture:", err)
+			continue
+		}
+		images[i] = img
+		if size == 0 {
+			size = img.Bounds().Dx()
+		}
+	}
+	if size == 0 {
+		size = defaultBlockTextureSize
+	}
+
+	layerBytes := size * size * 4
+	data := make([]uint8, layerBytes*max(len(images), 1))
+	for i, img := range images {
+		layer := data[i*layerBytes : (i+1)*layerBytes]
+		if img == nil {
+			fillMissingTexture(layer, size)
+		} else {
+			copyScaledImage(layer, size, img)
+		}
+	}
+
+	blockTextureSize = size
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, blockTextureArray)
+	gl.TexImage3D(gl.TEXTURE_2D_ARRAY, 0, gl.RGBA8, int32(size), int32(size), int32(max(len(images), 1)), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(data))
+	gl.GenerateMipmap(gl.TEXTURE_2D_ARRAY)
+
+	assets.Watch("textures/blocks", paths, func() error {
+		return loadBlockTextures(true)
+	})
+	return nil
+}
+
+// loadImage декодирует изображение из ресурсов
+func loadImage(name string) (image.Image, error) {
+	data, err := assets.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return img, nil
+}
+
+// copyScaledImage переносит изображение в слой size×size (RGBA) ближайшими пикселями
+func copyScaledImage(layer []uint8, size int, img image.Image) {
+	bounds := img.Bounds()
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			r, g, b, a := img.At(bounds.Min.X+x*bounds.Dx()/size, bounds.Min.Y+y*bounds.Dy()/size).RGBA()
+			i := (y*size + x) * 4
+			layer[i], layer[i+1], layer[i+2], layer[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+		}
+	}
+}
+
+// fillMissingTexture — заметная шахматка на месте ненайденной текстуры
+func fillMissingTexture(layer []uint8, size int) {
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			i := (y*size + x) * 4
+			if (x*2/size+y*2/size)%2 == 0 {
+				layer[i], layer[i+1], layer[i+2] = 255, 0, 255
+			} else {
+				layer[i], layer[i+1], layer[i+2] = 0, 0, 0
+			}
+			layer[i+3] = 255
+		}
+	}
+}
+
+// bindBlockTextures привязывает текстуры блоков к программе, рисующей чанки
+func bindBlockTextures(program *ShaderProgram) {
+	program.SetTexture("blockTextures", blockTextureUnit, gl.TEXTURE_2D_ARRAY, blockTextureArray)
+}
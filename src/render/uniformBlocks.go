@@ -0,0 +1,118 @@
+package render
+
+import (
+	"encoding/binary"
+	"engine/src/config"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Общие для программ мира данные кадра лежат в буферах uniform (раскладка std140):
+// камера, свет и туман (FrameData) — для основного шейдера, G-буфера и отложенного освещения,
+// каскады теней (ShadowData) — для них же и шейдера глубины. Буфер заполняется один раз
+// за проход, а не по uniform’у на каждую программу.
+
+// Точки привязки блоков uniform; программы подключаются к ним при сборке
+var uniformBlockBindings = map[string]uint32{
+	"FrameData":  0,
+	"ShadowData": 1,
+}
+
+// frameUniforms — раскладка блока FrameData (shaders/include/frame.glsl): vec3 и следующий float делят 16 байт
+type frameUniforms struct {
+	View             mgl32.Mat4
+	Projection       mgl32.Mat4
+	ViewPos          mgl32.Vec3
+	FogStart         float32
+	LightDir         mgl32.Vec3
+	FogEnd           float32
+	LightColor       mgl32.Vec3
+	UnderwaterFogEnd float32
+	AmbientColor     mgl32.Vec3
+	Underwater       uint32
+	FogColor         mgl32.Vec3
+	_                float32
+	UnderwaterColor  mgl32.Vec3
+	_                float32
+}
+
+// shadowUniforms — раскладка блока ShadowData (shaders/include/shadow_data.glsl)
+type shadowUniforms struct {
+	LightSpace    [maxShadowCascades]mgl32.Mat4
+	CascadeSplits [maxShadowCascades]float32
+	CascadeCount  int32
+	CascadeBlend  float32
+	_             [2]float32
+}
+
+var (
+	frameUBO  uint32
+	shadowUBO uint32
+)
+
+// bindUniformBlocks подключает блоки uniform программы к общим точкам привязки
+func bindUniformBlocks(program uint32) {
+	for name, binding := range uniformBlockBindings {
+		if index := gl.GetUniformBlockIndex(program, gl.Str(name+"\x00")); index != gl.INVALID_INDEX {
+			gl.UniformBlockBinding(program, index, binding)
+		}
+	}
+}
+
+// ensureUniformBuffers создаёт буферы блоков и привязывает их к точкам
+func ensureUniformBuffers() {
+	if frameUBO != 0 {
+		return
+	}
+	gl.GenBuffers(1, &frameUBO)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, frameUBO)
+	gl.BufferData(gl.UNIFORM_BUFFER, binary.Size(frameUniforms{}), nil, gl.DYNAMIC_DRAW)
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, uniformBlockBindings["FrameData"], frameUBO)
+
+	gl.GenBuffers(1, &shadowUBO)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, shadowUBO)
+	gl.BufferData(gl.UNIFORM_BUFFER, binary.Size(shadowUniforms{}), nil, gl.DYNAMIC_DRAW)
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, uniformBlockBindings["ShadowData"], shadowUBO)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+}
+
+// updateFrameUniforms заполняет FrameData для прохода с камерой view/projection в точке eye
+func updateFrameUniforms(view, projection mgl32.Mat4, eye mgl32.Vec3, Config *config.Config, sky SkyState, underwater bool) {
+	data := frameUniforms{
+		View:             view,
+		Projection:       projection,
+		ViewPos:          eye,
+		FogStart:         Config.FogStartLoc,
+		LightDir:         sky.LightDir,
+		FogEnd:           Config.FogEndLoc,
+		LightColor:       sky.LightColor,
+		UnderwaterFogEnd: underwaterFogEnd,
+		AmbientColor:     sky.AmbientColor,
+		FogColor:         sky.FogColor, // туман сливается с горизонтом
+		UnderwaterColor:  mgl32.Vec3(underwaterColor),
+	}
+	if underwater {
+		data.Underwater = 1
+	}
+	ensureUniformBuffers()
+	gl.BindBuffer(gl.UNIFORM_BUFFER, frameUBO)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, binary.Size(data), gl.Ptr(&data))
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+}
+
+// updateShadowUniforms заполняет ShadowData матрицами и границами каскадов кадра
+func updateShadowUniforms(cascades []ShadowCascade) {
+	data := shadowUniforms{
+		CascadeCount: int32(len(cascades)),
+		CascadeBlend: cascadeBlendFraction,
+	}
+	for i, c := range cascades {
+		data.LightSpace[i] = c.LightSpace
+		data.CascadeSplits[i] = c.Far
+	}
+	ensureUniformBuffers()
+	gl.BindBuffer(gl.UNIFORM_BUFFER, shadowUBO)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, binary.Size(data), gl.Ptr(&data))
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+}
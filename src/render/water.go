@@ -0,0 +1,203 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/player"
+	"engine/src/world"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// waterTarget — framebuffer с цветовой текстурой для проходов отражения и преломления
+type waterTarget struct {
+	FBO           uint32
+	Texture       uint32
+	Width, Height int32
+}
+
+var (
+	reflectionTarget waterTarget
+	refractionTarget waterTarget
+	waterNormalMap   uint32
+	waterTime        float64 // время анимации волн, секунды
+)
+
+// Параметры воды
+var (
+	waterDeepColor    = [3]float32{0.02, 0.18, 0.32} // цвет толщи воды, которым тонируется преломление
+	waterTint         = float32(0.35)                // доля цвета толщи в преломлении
+	waterDistortion   = float32(0.02)                // сила искажения текстур волнами
+	waterWaveScale    = float32(0.06)                // масштаб карты нормалей в мире
+	waterWaveSpeed    = float32(0.03)                // скорость прокрутки карты нормалей
+	waterNormalMapRes = 128
+)
+
+// CreateWaterTargets создаёт текстуры отражения и преломления (WaterResolutionScale от окна)
+// и процедурную карту нормалей волн. Уровень воды берётся из SeaLevel, как при генерации мира.
+func CreateWaterTargets(Config *config.Config) {
+	waterLevel = float32(int(Config.SeaLevel * float64(Config.ChunkY)))
+
+	width := int32(float32(Config.Width) * Config.WaterResolutionScale)
+	height := int32(float32(Config.Height) * Config.WaterResolutionScale)
+	reflectionTarget = createWaterTarget(width, height)
+	refractionTarget = createWaterTarget(width, height)
+
+	createWaterNormalMap()
+}
+
+func createWaterTarget(width, height int32) waterTarget {
+	t := waterTarget{Width: width, Height: height}
+	gl.GenFramebuffers(1, &t.FBO)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.FBO)
+
+	// Текстура цвета
+	gl.GenTextures(1, &t.Texture)
+	gl.BindTexture(gl.TEXTURE_2D, t.Texture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.Texture, 0)
+
+	// Рендербуфер глубины
+	var rbo uint32
+	gl.GenRenderbuffers(1, &rbo)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rbo)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, rbo)
+
+	// Проверка статуса
+	if gl.CheckFramebufferStatus(gl.FRAMEBUFFER) != gl.FRAMEBUFFER_COMPLETE {
+		panic("Water Framebuffer is not complete!")
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	return t
+}
+
+// Волны карты нормалей: целые частоты по осям, чтобы текстура бесшовно повторялась
+var waterWaves = []struct {
+	KX, KY    float64
+	Amplitude float64
+	Phase     float64
+}{
+	{1, 2, 1.0, 0.0},
+	{3, -1, 0.6, 1.3},
+	{-2, 3, 0.45, 2.1},
+	{5, 4, 0.25, 0.7},
+	{-7, 2, 0.15, 4.2},
+	{4, -9, 0.1, 3.3},
+}
+
+// createWaterNormalMap строит карту нормалей из суммы синусоид (z вдоль нормали поверхности)
+func createWaterNormalMap() {
+	res := waterNormalMapRes
+	pixels := make([]uint8, res*res*3)
+	for y := 0; y < res; y++ {
+		for x := 0; x < res; x++ {
+			u, v := float64(x)/float64(res), float64(y)/float64(res)
+			var dx, dy float64
+			for _, w := range waterWaves {
+				arg := 2*math.Pi*(w.KX*u+w.KY*v) + w.Phase
+				d := w.Amplitude * math.Cos(arg) * 2 * math.Pi
+				dx += d * w.KX
+				dy += d * w.KY
+			}
+			// Высота в пикселях карты мала: нормаль почти вертикальна
+			n := mgl32.Vec3{float32(-dx * 0.02), float32(-dy * 0.02), 1}.Normalize()
+			i := (y*res + x) * 3
+			pixels[i+0] = uint8((n.X()*0.5 + 0.5) * 255)
+			pixels[i+1] = uint8((n.Y()*0.5 + 0.5) * 255)
+			pixels[i+2] = uint8((n.Z()*0.5 + 0.5) * 255)
+		}
+	}
+
+	gl.GenTextures(1, &waterNormalMap)
+	gl.BindTexture(gl.TEXTURE_2D, waterNormalMap)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB8, int32(res), int32(res), 0, gl.RGB, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+}
+
+// RenderRefraction рисует в текстуру преломления то, что находится по другую сторону
+// поверхности воды от камеры (дно, если камера над водой). Сама вода не рисуется.
+func RenderRefraction(
+	program *ShaderProgram,
+	config *config.Config,
+	worldObj *world.World,
+	cameraObj *player.Camera,
+	sky SkyState,
+) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, refractionTarget.FBO)
+	gl.Viewport(0, 0, refractionTarget.Width, refractionTarget.Height)
+
+	eye := cameraObj.EyePosition()
+	underwater := eye.Y() < waterLevel
+	gl.ClearColor(waterDeepColor[0], waterDeepColor[1], waterDeepColor[2], 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	view := cameraObj.GetViewMatrix()
+	projection := cameraObj.GetProjectionMatrix(float32(config.Width) / float32(config.Height))
+	if underwater {
+		renderSky(view, projection, eye, sky, false) // снизу сквозь воду видно небо
+	}
+
+	// Плоскость с запасом в блок, чтобы у берега не было щелей
+	plane := mgl32.Vec4{0, -1, 0, waterLevel + 1}
+	if underwater {
+		plane = mgl32.Vec4{0, 1, 0, -waterLevel + 1}
+	}
+	setClipPlane(program, plane)
+	gl.Enable(gl.CLIP_DISTANCE0)
+	renderWorldPass(program, config, view, projection, eye, sky, passRefraction, false)
+	gl.Disable(gl.CLIP_DISTANCE0)
+	setClipPlane(program, mgl32.Vec4{})
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// setClipPlane задаёт плоскость отсечения (ax + by + cz + d >= 0 остаётся)
+func setClipPlane(program *ShaderProgram, plane mgl32.Vec4) {
+	program.Use()
+	program.SetVec4("clipPlane", plane)
+}
+
+// setupWaterUniforms привязывает текстуры отражения, преломления и волн и параметры воды
+func setupWaterUniforms(program *ShaderProgram, Config *config.Config, pass int32) {
+	// Воду рисует только проход полупрозрачного, и он же пропускает непрозрачное
+	auxiliary := pass == passReflection || pass == passRefraction
+	var waterPass, translucentOnly int32
+	if pass == passTranslucent {
+		translucentOnly = 1
+	} else {
+		waterPass = 1
+	}
+	program.SetInt("waterPass", waterPass)
+	program.SetInt("translucentOnly", translucentOnly)
+	program.SetFloat("time", float32(waterTime))
+	program.SetVec2("screenSize", float32(Config.Width), float32(Config.Height))
+	program.SetVec3("waterDeepColor", mgl32.Vec3(waterDeepColor))
+	program.SetFloat("waterTint", waterTint)
+	program.SetFloat("waterDistortion", waterDistortion)
+	program.SetFloat("waterWaveScale", waterWaveScale)
+	program.SetFloat("waterWaveSpeed", waterWaveSpeed)
+
+	// Во вспомогательных проходах эти текстуры — цели отрисовки, читать их нельзя
+	reflection, refraction := reflectionTarget.Texture, refractionTarget.Texture
+	if auxiliary {
+		reflection, refraction = 0, 0
+	}
+
+	program.SetTexture("reflectionMap", 2, gl.TEXTURE_2D, reflection)
+
+	program.SetTexture("refractionMap", 3, gl.TEXTURE_2D, refraction)
+
+	program.SetTexture("waterNormalMap", 4, gl.TEXTURE_2D, waterNormalMap)
+}
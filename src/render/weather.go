@@ -0,0 +1,141 @@
+package render
+
+import (
+	"engine/src/config"
+	"engine/src/profiler"
+	"engine/src/world"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Карта высот столбцов вокруг камеры: по ней частицы осадков гаснут под крышами
+// и деревьями, а поверхности намокают только под открытым небом
+const (
+	heightMapSize    = 128
+	heightMapRefresh = 0.25 // секунд между обновлениями (SetBlock меняет высоты)
+)
+
+var (
+	heightMapTexture uint32
+	heightMapOrigin  [2]int // мировые X, Z левого нижнего столбца карты
+	heightMapData    = make([]float32, heightMapSize*heightMapSize)
+	heightMapTimer   float64
+
+	precipitationProgram *ShaderProgram
+	precipitationVAO     uint32
+)
+
+// Параметры осадков
+var (
+	precipitationHeight = float32(40.0)               // высота области частиц вокруг камеры
+	precipitationWind   = mgl32.Vec2{0.15, 0.05}      // снос частиц ветром на единицу падения
+	rainColor           = [3]float32{0.7, 0.75, 0.85} // цвет струй дождя при полном свете
+	snowColor           = [3]float32{1.0, 1.0, 1.0}
+)
+
+// CreateWeather создаёт карту высот и шейдер частиц осадков.
+// Частицы не хранятся в буферах: положение каждой вычисляется в шейдере по её номеру и времени.
+func CreateWeather() {
+	gl.GenTextures(1, &heightMapTexture)
+	gl.BindTexture(gl.TEXTURE_2D, heightMapTexture)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.R32F, heightMapSize, heightMapSize, 0, gl.RED, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+
+	precipitationProgram = initProgram("precipitation", compilePrecipitationShader)
+	gl.GenVertexArrays(1, &precipitationVAO)
+}
+
+// UpdateHeightMap переносит высоты столбцов вокруг камеры в текстуру — сразу, если камера
+// сместилась на четверть карты, иначе раз в heightMapRefresh секунд
+func UpdateHeightMap(worldObj *world.World, eye mgl32.Vec3, deltaTime float64) {
+	origin := [2]int{
+		int(math.Floor(float64(eye.X()))) - heightMapSize/2,
+		int(math.Floor(float64(eye.Z()))) - heightMapSize/2,
+	}
+	heightMapTimer -= deltaTime
+	moved := abs(origin[0]-heightMapOrigin[0]) > heightMapSize/4 || abs(origin[1]-heightMapOrigin[1]) > heightMapSize/4
+	if heightMapTimer > 0 && !moved {
+		return
+	}
+	heightMapTimer = heightMapRefresh
+	heightMapOrigin = origin
+
+	worldObj.FillHeightMap(origin[0], origin[1], heightMapSize, 0, heightMapData)
+	gl.BindTexture(gl.TEXTURE_2D, heightMapTexture)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, heightMapSize, heightMapSize, gl.RED, gl.FLOAT, gl.Ptr(heightMapData))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// bindHeightMap привязывает карту высот к текстурному блоку unit программы program
+func bindHeightMap(program *ShaderProgram, unit uint32) {
+	program.SetTexture("heightMap", unit, gl.TEXTURE_2D, heightMapTexture)
+	program.SetIVec2("heightMapOrigin", int32(heightMapOrigin[0]), int32(heightMapOrigin[1]))
+	program.SetInt("heightMapSize", heightMapSize)
+}
+
+// setupWeatherUniforms — намокание поверхностей в основном шейдере (карта высот на блоке 5)
+func setupWeatherUniforms(program *ShaderProgram, sky SkyState) {
+	program.SetFloat("wetness", sky.Weather.Wetness)
+	bindHeightMap(program, 5)
+}
+
+// renderPrecipitation рисует дождь или снег вокруг камеры поверх уже нарисованного мира
+func renderPrecipitation(view, projection mgl32.Mat4, eye mgl32.Vec3, sky SkyState, Config *config.Config) {
+	weather := sky.Weather
+	if weather.Kind == world.WeatherClear || weather.Intensity <= 0 || !precipitationProgram.Ready() {
+		return
+	}
+	count := int32(float32(Config.PrecipitationParticles) * weather.Intensity)
+	if count == 0 {
+		return
+	}
+
+	snow := weather.Kind == world.WeatherSnow
+	color := rainColor
+	if snow {
+		color = snowColor
+	}
+	// Частицы освещены небом: ночью и в грозу они темнее, вспышка молнии их высвечивает
+	light := sky.AmbientColor.Add(sky.LightColor.Mul(0.5)).Add(mgl32.Vec3{1, 1, 1}.Mul(weather.Flash))
+
+	precipitationProgram.Use()
+	precipitationProgram.SetMat4("view", view)
+	precipitationProgram.SetMat4("projection", projection)
+	precipitationProgram.SetVec3("cameraPos", eye)
+	precipitationProgram.SetFloat("time", float32(waterTime))
+	precipitationProgram.SetFloat("radius", Config.PrecipitationRadius)
+	precipitationProgram.SetFloat("boxHeight", precipitationHeight)
+	precipitationProgram.SetVec2("wind", precipitationWind.X(), precipitationWind.Y())
+	var snowFlag int32
+	if snow {
+		snowFlag = 1
+	}
+	precipitationProgram.SetInt("snow", snowFlag)
+	precipitationProgram.SetVec3("particleColor", mgl32.Vec3{color[0] * light.X(), color[1] * light.Y(), color[2] * light.Z()})
+	bindHeightMap(precipitationProgram, 0)
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DepthMask(false)
+	gl.BindVertexArray(precipitationVAO)
+	gl.DrawArraysInstanced(gl.TRIANGLES, 0, 6, count)
+	profiler.CountDraw(2 * int(count))
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.Disable(gl.BLEND)
+}
+
+func compilePrecipitationShader() (*ShaderProgram, error) {
+	return loadProgram("precipitation", "precipitation.vert", "precipitation.frag")
+}
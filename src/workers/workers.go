@@ -9,29 +9,30 @@ import (
 	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
-	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
-func ChunkCreatorWorker(w *world.World, genCh, delCh <-chan [2]int, vramCh chan [3]uint32, Config *config.Config) {
+func ChunkCreatorWorker(w *world.World, Config *config.Config) {
 	go func() {
 		for {
-
-			coords := <-genCh
-			// Если приходят координаты для генерации
+			// Берём самый приоритетный чанк из планировщика
+			coords, ok := w.Scheduler.Next()
+			if !ok {
+				return
+			}
 			x, z := coords[0], coords[1]
 			w.GenerateChunk(x, z, Config)
-
+			w.Scheduler.Done(coords)
 		}
 	}()
 }
-func ChunkDeleterWorker(w *world.World, genCh, delCh <-chan [2]int, vramCh chan [3]uint32) {
+func ChunkDeleterWorker(w *world.World, delCh <-chan [2]int) {
 	go func() {
 		for {
 
 			coords := <-delCh
 			// Если приходят координаты для удаления
 			x, z := coords[0], coords[1]
-			w.RemoveChunk(x, z, vramCh)
+			w.RemoveChunk(x, z)
 
 		}
 	}()
@@ -39,7 +40,7 @@ func ChunkDeleterWorker(w *world.World, genCh, delCh <-chan [2]int, vramCh chan
 func UpdateWorld(
 	worldObj *world.World,
 	cameraObj *player.Camera,
-	chunkGenCh, chunkDelCh chan [2]int,
+	chunkDelCh chan [2]int,
 	Config *config.Config,
 ) {
 	go func() {
@@ -47,8 +48,7 @@ func UpdateWorld(
 		defer ticker.Stop()
 
 		for range ticker.C {
-			playerPos := cameraObj.Position
-			worldObj.UpdateChunks(int(playerPos.X()), int(playerPos.Z()), Config.ChunkDist, chunkGenCh, chunkDelCh)
+			worldObj.UpdateChunks(cameraObj.FeetPosition(), cameraObj.Front(), Config.ChunkDist, Config.LODDistances, chunkDelCh)
 
 			// Проверяем завершение работы программы или других условий
 			if cameraObj == nil || worldObj == nil { // Условие для выхода из горутины
@@ -57,16 +57,6 @@ func UpdateWorld(
 		}
 	}()
 }
-func InitMouseHandler(window *glfw.Window, camera *player.Camera) {
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-	go func() {
-		for !window.ShouldClose() {
-			xpos, ypos := window.GetCursorPos()
-			camera.ProcessMouse(xpos, ypos)
-			time.Sleep(10 * time.Millisecond)
-		}
-	}()
-}
 func MonitorMemoryStats(
 	cameraObj *player.Camera,
 	worldObj *world.World,
@@ -94,7 +84,7 @@ func MonitorMemoryStats(
 			debugInfo := []string{
 				fmt.Sprintf("FPS: %.2f", 1.0/deltaTime),
 				fmt.Sprintf("Camera Position: X=%.2f Y=%.2f Z=%.2f", cameraObj.Position.X(), cameraObj.Position.Y(), cameraObj.Position.Z()),
-				fmt.Sprintf("Chunks Loaded: %d", len(worldObj.Chunks)),
+				fmt.Sprintf("Chunks Loaded: %d", worldObj.ChunkCount()),
 				fmt.Sprintf("Allocated RAM: %.2f MB", float64(memStats.Alloc)/1024/1024),
 				fmt.Sprintf("Total Allocated RAM: %.2f MB", float64(memStats.TotalAlloc)/1024/1024),
 				fmt.Sprintf("System RAM: %.2f MB", float64(memStats.Sys)/1024/1024),
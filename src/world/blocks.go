@@ -0,0 +1,283 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Идентификаторы блоков с особым поведением
+const (
+	BlockAir       uint8 = 0
+	BlockWater     uint8 = 7
+	BlockSnowLayer uint8 = 13 // тонкий слой снега, нарастающий в снегопад
+	BlockTorch     uint8 = 14 // светящиеся блоки — источники точечного света
+	BlockLava      uint8 = 15
+)
+
+// BlockLight — свет, который излучает блок
+type BlockLight struct {
+	Color  mgl32.Vec3 // цвет с интенсивностью
+	Radius float32    // на этом расстоянии свет гаснет полностью
+}
+
+// blockLights — излучение светящихся блоков по идентификатору
+var blockLights = map[uint8]BlockLight{
+	BlockTorch: {Color: mgl32.Vec3{1.6, 1.1, 0.55}, Radius: 10},
+	BlockLava:  {Color: mgl32.Vec3{2.0, 0.7, 0.2}, Radius: 8},
+}
+
+// BlockEmission возвращает свет блока; ok == false, если блок не светится
+func BlockEmission(id uint8) (light BlockLight, ok bool) {
+	light, ok = blockLights[id]
+	return light, ok
+}
+
+// PointLight — точечный источник света в мировых координатах
+type PointLight struct {
+	Position mgl32.Vec3
+	Color    mgl32.Vec3
+	Radius   float32
+}
+
+// NamedBlocks — блоки, которые игрок может выбрать для установки
+var NamedBlocks = map[string]Block{
+	"dirt":  {Id: 1, Color: [3]float32{0.8, 0.6, 0.4}},
+	"stone": {Id: 3, Color: [3]float32{0.5, 0.5, 0.5}},
+	"wood":  {Id: 5, Color: [3]float32{0.5, 0.3, 0.1}},
+	"sand":  {Id: 8, Color: [3]float32{0.9, 0.8, 0.4}},
+	"torch": {Id: BlockTorch, Color: [3]float32{1.0, 0.8, 0.45}},
+	"lava":  lavaBlock,
+}
+
+// lavaBlock — лава выходов в горах
+var lavaBlock = Block{Id: BlockLava, Color: [3]float32{1.0, 0.4, 0.1}}
+
+// SnowLayerHeight — высота слоя снега в долях блока
+const SnowLayerHeight = 0.125
+
+// SnowLayerColor — цвет слоя снега
+var SnowLayerColor = [3]float32{0.95, 0.97, 1.0}
+
+// IsLiquid — блок является жидкостью (через него можно плавать)
+func IsLiquid(id uint8) bool {
+	return id == BlockWater
+}
+
+// IsSolid — блок участвует в коллизиях (не воздух, не жидкость и не слой снега)
+func IsSolid(id uint8) bool {
+	return id != BlockAir && !IsLiquid(id) && id != BlockSnowLayer
+}
+
+// blockHeight — высота формы блока: слой снега тоньше обычного куба
+func blockHeight(id uint8) float32 {
+	if id == BlockSnowLayer {
+		return SnowLayerHeight
+	}
+	return 1
+}
+
+// Материалы вершин меша: шейдер по ним выбирает способ отрисовки
+const (
+	MaterialOpaque   float32 = 0
+	MaterialWater    float32 = 1
+	MaterialEmissive float32 = 2 // светится сам, освещение на него не действует
+)
+
+// VertexFloats — число float на вершину меша: позиция, нормаль, цвет, материал,
+// текстурные координаты и слой массива текстур
+const VertexFloats = 13
+
+// FaceTexture — текстура грани блока: имя файла textures/blocks/<имя>.png без расширения
+type FaceTexture struct {
+	Name string `json:"texture"`
+	Tint bool   `json:"tint"` // текстура умножается на Block.Color (трава и листва окрашиваются по биому)
+}
+
+// BlockTextures — текстуры верхней, боковых и нижней граней блока
+type BlockTextures struct {
+	Top    FaceTexture `json:"top"`
+	Side   FaceTexture `json:"side"`
+	Bottom FaceTexture `json:"bottom"`
+}
+
+// tinted — одна окрашиваемая текстура на всех гранях
+func tinted(name string) BlockTextures {
+	face := FaceTexture{Name: name, Tint: true}
+	return BlockTextures{Top: face, Side: face, Bottom: face}
+}
+
+// defaultBlockTextures — текстуры граней по идентификатору блока, пока описания блоков
+// не загружены из ресурсов. Вода и блоки без записи рисуются одним цветом (NoTexture)
+var defaultBlockTextures = map[uint8]BlockTextures{
+	1:  tinted("dirt"),
+	2:  grassTextures,
+	3:  tinted("stone"),
+	5:  {Top: FaceTexture{"log_top", true}, Side: FaceTexture{"log_side", true}, Bottom: FaceTexture{"log_top", true}},
+	6:  tinted("leaves"),
+	8:  tinted("sand"),
+	9:  grassTextures,
+	10: tinted("cobblestone"),
+	11: grassTextures,
+	12: tinted("snow"),
+
+	BlockSnowLayer: tinted("snow"),
+	BlockTorch:     tinted("torch"),
+	BlockLava:      tinted("lava"),
+}
+
+// Трава: верх окрашивается биомом, бок — земля с полосой травы в своих цветах
+var grassTextures = BlockTextures{
+	Top:    FaceTexture{Name: "grass_top", Tint: true},
+	Side:   FaceTexture{Name: "grass_side"},
+	Bottom: FaceTexture{Name: "dirt", Tint: true},
+}
+
+// NoTexture — слой граней без текстуры
+const NoTexture float32 = -1
+
+// textureSet — текстуры граней блоков и слои массива текстур под ними.
+// Меши строятся в рабочих горутинах, поэтому набор не меняется, а заменяется целиком.
+type textureSet struct {
+	faces  map[uint8]BlockTextures
+	names  []string // все текстуры по алфавиту; индекс имени — слой массива текстур
+	layers map[string]float32
+}
+
+var blockTextures atomic.Pointer[textureSet]
+
+func init() {
+	blockTextures.Store(newTextureSet(defaultBlockTextures))
+}
+
+func newTextureSet(faces map[uint8]BlockTextures) *textureSet {
+	set := &textureSet{faces: faces, layers: map[string]float32{}}
+	for _, textures := range faces {
+		for _, face := range []FaceTexture{textures.Top, textures.Side, textures.Bottom} {
+			if face.Name != "" {
+				set.layers[face.Name] = 0
+			}
+		}
+	}
+	for name := range set.layers {
+		set.names = append(set.names, name)
+	}
+	sort.Strings(set.names)
+	for i, name := range set.names {
+		set.layers[name] = float32(i)
+	}
+	return set
+}
+
+// TextureNames возвращает имена текстур блоков в порядке слоёв массива текстур
+func TextureNames() []string {
+	return blockTextures.Load().names
+}
+
+// faceTexture возвращает слой текстуры грани блока id (offsetY — направление грани по Y)
+// и нужно ли окрашивать её цветом блока
+func faceTexture(id uint8, offsetY int) (layer float32, tint bool) {
+	set := blockTextures.Load()
+	textures, ok := set.faces[id]
+	if !ok {
+		return NoTexture, true
+	}
+	face := textures.Side
+	switch offsetY {
+	case 1:
+		face = textures.Top
+	case -1:
+		face = textures.Bottom
+	}
+	if face.Name == "" {
+		return NoTexture, true
+	}
+	return set.layers[face.Name], face.Tint
+}
+
+// BlockDefinition — описание блока в ресурсах (blocks.json)
+type BlockDefinition struct {
+	Id    uint8      `json:"id"`
+	Name  string     `json:"name"`  // имя для команды block; без имени блок нельзя выбрать
+	Color [3]float32 `json:"color"` // цвет блока, поставленного игроком
+	BlockTextures
+}
+
+// LoadBlockDefinitions заменяет текстуры граней и выбираемые игроком блоки описаниями
+// из JSON-массива BlockDefinition. При ошибке остаются прежние описания.
+// Вызывается из GL-потока: NamedBlocks читают только команды консоли.
+// Уже построенные меши нужно перестроить (RemeshAll).
+func LoadBlockDefinitions(data []byte) error {
+	var definitions []BlockDefinition
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return err
+	}
+	faces := make(map[uint8]BlockTextures, len(definitions))
+	named := map[string]Block{}
+	for _, def := range definitions {
+		if _, dup := faces[def.Id]; dup {
+			return fmt.Errorf("block %d is defined twice", def.Id)
+		}
+		faces[def.Id] = def.BlockTextures
+		if def.Name != "" {
+			named[def.Name] = Block{Id: def.Id, Color: def.Color}
+		}
+	}
+	blockTextures.Store(newTextureSet(faces))
+	NamedBlocks = named
+	return nil
+}
+
+// faceUV — текстурные координаты точки грани в единицах блока. Текстура повторяется
+// каждый блок, поэтому квадрат любого размера покрыт ею без растяжения; v растёт вниз,
+// как строки изображения
+func faceUV(offsetX, offsetY, offsetZ int, px, py, pz float32) (u, v float32) {
+	switch {
+	case offsetY != 0:
+		return px, pz * float32(offsetY)
+	case offsetX != 0:
+		return -pz * float32(offsetX), -py
+	default:
+		return px * float32(offsetZ), -py
+	}
+}
+
+// BlockMaterial возвращает материал, которым рисуются грани блока
+func BlockMaterial(id uint8) float32 {
+	if id == BlockWater {
+		return MaterialWater
+	}
+	if _, ok := blockLights[id]; ok {
+		return MaterialEmissive
+	}
+	return MaterialOpaque
+}
+
+// faceVisible — видна ли грань блока id, граничащая с блоком neighbor (offsetY — направление грани по Y).
+// Сквозь воду видны грани дна, но грани между двумя блоками воды не рисуются.
+// Слой снега закрывает верхнюю грань блока под ним, а боковые грани соседних слоёв скрыты.
+func faceVisible(id, neighbor uint8, offsetY int) bool {
+	if neighbor == BlockAir {
+		return true
+	}
+	if neighbor == BlockSnowLayer {
+		return offsetY != 1 && id != BlockSnowLayer
+	}
+	return IsLiquid(neighbor) && !IsLiquid(id)
+}
+
+// IsLiquidAt — находится ли точка мира внутри жидкости
+func (w *World) IsLiquidAt(x, y, z float32) bool {
+	return IsLiquid(w.GetBlock(floorInt(x), floorInt(y), floorInt(z)).Id)
+}
+
+func floorInt(v float32) int {
+	i := int(v)
+	if float32(i) > v {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,131 @@
+package world
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ChunkState — стадия жизненного цикла чанка
+type ChunkState int32
+
+const (
+	ChunkGenerating ChunkState = iota // блоки ещё генерируются воркером
+	ChunkGenerated                    // блоки готовы, меша ещё нет
+	ChunkMeshing                      // строится меш
+	ChunkMeshReady                    // меш отправлен GL-потоку
+	ChunkUploaded                     // меш загружен в видеопамять
+	ChunkUnloading                    // чанк выгружается, новые меши не публикуются
+)
+
+func (s ChunkState) String() string {
+	switch s {
+	case ChunkGenerating:
+		return "Generating"
+	case ChunkGenerated:
+		return "Generated"
+	case ChunkMeshing:
+		return "Meshing"
+	case ChunkMeshReady:
+		return "MeshReady"
+	case ChunkUploaded:
+		return "Uploaded"
+	case ChunkUnloading:
+		return "Unloading"
+	}
+	return "Unknown"
+}
+
+// ChunkMesh — неизменяемый результат мешинга, передаваемый GL-потоку через World.MeshCh.
+// Сообщение с Unload == true означает, что GPU-ресурсы чанка нужно освободить.
+type ChunkMesh struct {
+	Coord    [2]int
+	ChunkID  uint64 // уникален для каждого сгенерированного чанка, даже на той же координате
+	Version  uint64
+	Vertices []float32
+	Indices  []uint32
+	Lights   []PointLight        // точечные источники света чанка
+	Bounds   [2]mgl32.Vec3       // AABB чанка в мировых координатах
+	LOD      int                 // уровень детализации меша, 0 — полный
+	Sections []SectionVisibility // проходы между гранями секций для отсечения пещер
+	Unload   bool
+
+	chunk *Chunk
+}
+
+// MarkUploaded переводит чанк в состояние Uploaded после загрузки меша в видеопамять.
+// Не берёт lifeMu: публикующий генератор может держать его, ожидая место в канале.
+func (m *ChunkMesh) MarkUploaded() {
+	if m.chunk == nil || m.chunk.sentVersion.Load() != m.Version {
+		return
+	}
+	m.chunk.state.CompareAndSwap(int32(ChunkMeshReady), int32(ChunkUploaded))
+}
+
+// Структура чанка
+type Chunk struct {
+	Coord               [2]int
+	ID                  uint64
+	Blocks              []Block // Одномерный массив блоков (защищён mu)
+	SizeX, SizeY, SizeZ int
+
+	heights []int16 // карта высот: y+1 верхнего непустого блока столбца x + z*SizeX (защищена mu)
+
+	mu          sync.RWMutex // блоки: читают мешинг и GetBlock, пишет SetBlock
+	lifeMu      sync.Mutex   // переходы состояния и публикация мешей
+	state       atomic.Int32
+	meshVersion atomic.Uint64 // номер последнего запущенного мешинга
+	sentVersion atomic.Uint64 // номер последнего опубликованного меша (пишется под lifeMu)
+	lod         atomic.Int32  // уровень детализации последнего запущенного мешинга
+}
+
+// State возвращает текущую стадию жизненного цикла
+func (chunk *Chunk) State() ChunkState {
+	return ChunkState(chunk.state.Load())
+}
+
+// LOD возвращает уровень детализации последнего запущенного мешинга
+func (chunk *Chunk) LOD() int {
+	return int(chunk.lod.Load())
+}
+
+func (chunk *Chunk) setState(s ChunkState) {
+	chunk.state.Store(int32(s))
+}
+
+// beginMeshing переводит чанк в Meshing и выдаёт номер версии меша.
+// Возвращает false, если чанк уже выгружается.
+func (chunk *Chunk) beginMeshing() (uint64, bool) {
+	chunk.lifeMu.Lock()
+	defer chunk.lifeMu.Unlock()
+	if chunk.State() == ChunkUnloading {
+		return 0, false
+	}
+	chunk.setState(ChunkMeshing)
+	return chunk.meshVersion.Add(1), true
+}
+
+// publishMesh отправляет меш GL-потоку, если он новее уже отправленного и чанк не выгружается.
+// Отправка под lifeMu гарантирует, что после сообщения о выгрузке меши чанка не придут.
+func (chunk *Chunk) publishMesh(mesh *ChunkMesh, out chan<- *ChunkMesh) {
+	chunk.lifeMu.Lock()
+	defer chunk.lifeMu.Unlock()
+	if chunk.State() == ChunkUnloading || mesh.Version <= chunk.sentVersion.Load() {
+		return
+	}
+	chunk.sentVersion.Store(mesh.Version)
+	// Пока строился этот меш, мог стартовать более новый — тогда состояние остаётся Meshing
+	if chunk.meshVersion.Load() == mesh.Version {
+		chunk.setState(ChunkMeshReady)
+	}
+	out <- mesh
+}
+
+// unload помечает чанк выгружаемым и просит GL-поток освободить его буферы
+func (chunk *Chunk) unload(out chan<- *ChunkMesh) {
+	chunk.lifeMu.Lock()
+	defer chunk.lifeMu.Unlock()
+	chunk.setState(ChunkUnloading)
+	out <- &ChunkMesh{Coord: chunk.Coord, ChunkID: chunk.ID, Unload: true}
+}
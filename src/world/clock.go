@@ -0,0 +1,122 @@
+package world
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TicksPerSecond — скорость хода мировых часов
+const TicksPerSecond = 20
+
+// DefaultDayLength — длина суток по умолчанию в тиках (20 минут)
+const DefaultDayLength = 24000
+
+// Clock — мировые часы: тики от создания мира, длина суток и остановка времени.
+// Полночь — начало суток, полдень — половина DayLength.
+type Clock struct {
+	mu        sync.Mutex
+	ticks     int64
+	partial   float64 // доля тика, накопленная между кадрами
+	dayLength int64
+	frozen    bool
+}
+
+// ClockState — снимок часов на кадр
+type ClockState struct {
+	Ticks     int64   // тиков с создания мира
+	DayLength int64   // тиков в сутках
+	Day       int64   // номер текущих суток, с 0
+	Fraction  float64 // доля прошедших суток: 0 — полночь, 0.5 — полдень
+	Frozen    bool
+}
+
+// NewClock создаёт часы, стоящие на 8 утра первых суток
+func NewClock(dayLength int64) *Clock {
+	if dayLength <= 0 {
+		dayLength = DefaultDayLength
+	}
+	return &Clock{ticks: dayLength / 3, dayLength: dayLength}
+}
+
+// Advance продвигает часы на deltaTime секунд, если время не остановлено
+func (c *Clock) Advance(deltaTime float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		return
+	}
+	c.partial += deltaTime * TicksPerSecond
+	whole := int64(c.partial)
+	c.ticks += whole
+	c.partial -= float64(whole)
+}
+
+// Now возвращает снимок часов; доля суток учитывает неполный тик для плавного хода солнца
+func (c *Clock) Now() ClockState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timeOfDay := c.ticks % c.dayLength
+	return ClockState{
+		Ticks:     c.ticks,
+		DayLength: c.dayLength,
+		Day:       c.ticks / c.dayLength,
+		Fraction:  (float64(timeOfDay) + c.partial) / float64(c.dayLength),
+		Frozen:    c.frozen,
+	}
+}
+
+// SetTimeOfDay переводит часы вперёд до ближайшего наступления тика tick суток:
+// если это время сегодня уже прошло, часы уходят на следующие сутки
+func (c *Clock) SetTimeOfDay(tick int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tick %= c.dayLength
+	if tick < 0 {
+		tick += c.dayLength
+	}
+	dayStart := c.ticks - c.ticks%c.dayLength
+	if tick < c.ticks-dayStart {
+		dayStart += c.dayLength
+	}
+	c.ticks = dayStart + tick
+	c.partial = 0
+}
+
+// Add сдвигает часы на ticks (можно назад, но не раньше создания мира)
+func (c *Clock) Add(ticks int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ticks += ticks
+	if c.ticks < 0 {
+		c.ticks = 0
+	}
+}
+
+// SetFrozen останавливает или запускает ход времени
+func (c *Clock) SetFrozen(frozen bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = frozen
+}
+
+// TicksAt переводит время суток в тики для длины суток часов
+func (c *Clock) TicksAt(hours, minutes int) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return (int64(hours)*60 + int64(minutes)) * c.dayLength / (24 * 60)
+}
+
+// Clock24 — время суток в часах и минутах
+func (s ClockState) Clock24() (hours, minutes int) {
+	total := int(s.Fraction * 24 * 60)
+	return total / 60 % 24, total % 60
+}
+
+func (s ClockState) String() string {
+	h, m := s.Clock24()
+	frozen := ""
+	if s.Frozen {
+		frozen = " (frozen)"
+	}
+	return fmt.Sprintf("Day %d, %02d:%02d, tick %d%s", s.Day+1, h, m, s.Ticks%s.DayLength, frozen)
+}
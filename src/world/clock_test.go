@@ -0,0 +1,28 @@
+package world
+
+import "testing"
+
+func TestClockSetTimeOfDayMovesForward(t *testing.T) {
+	c := NewClock(DefaultDayLength)
+	evening := c.TicksAt(18, 0)
+	morning := c.TicksAt(6, 0)
+
+	c.SetTimeOfDay(evening) // 08:00 → 18:00 тех же суток
+	if now := c.Now(); now.Day != 0 || now.Ticks != evening {
+		t.Fatalf("set 18:00 from 08:00: day %d, ticks %d; want day 0, ticks %d", now.Day, now.Ticks, evening)
+	}
+
+	c.SetTimeOfDay(morning) // 18:00 → 06:00 следующих суток
+	now := c.Now()
+	if now.Day != 1 || now.Ticks != DefaultDayLength+morning {
+		t.Errorf("set 06:00 from 18:00: day %d, ticks %d; want day 1, ticks %d", now.Day, now.Ticks, DefaultDayLength+morning)
+	}
+	if h, m := now.Clock24(); h != 6 || m != 0 {
+		t.Errorf("Clock24() = %02d:%02d, want 06:00", h, m)
+	}
+
+	c.SetTimeOfDay(morning) // то же время — часы стоят на месте
+	if got := c.Now().Ticks; got != now.Ticks {
+		t.Errorf("set to the current time moved the clock from %d to %d", now.Ticks, got)
+	}
+}
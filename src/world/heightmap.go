@@ -0,0 +1,95 @@
+package world
+
+// computeHeights строит карту высот чанка после генерации
+func (chunk *Chunk) computeHeights() {
+	chunk.heights = make([]int16, chunk.SizeX*chunk.SizeZ)
+	for x := 0; x < chunk.SizeX; x++ {
+		for z := 0; z < chunk.SizeZ; z++ {
+			chunk.updateColumnHeight(x, z)
+		}
+	}
+}
+
+// updateColumnHeight пересчитывает высоту столбца. Вызывающий держит chunk.mu на запись
+// (или чанк ещё не опубликован в мире)
+func (chunk *Chunk) updateColumnHeight(x, z int) {
+	height := 0
+	for y := chunk.SizeY - 1; y >= 0; y-- {
+		if chunk.Blocks[blockIndex(x, y, z, chunk.SizeX, chunk.SizeY, chunk.SizeZ)].Id != BlockAir {
+			height = y + 1
+			break
+		}
+	}
+	chunk.heights[x+z*chunk.SizeX] = int16(height)
+}
+
+// locate переводит мировые координаты столбца в координаты чанка и локальные внутри него
+func (w *World) locate(x, z int) (coord [2]int, lx, lz int) {
+	cx, cz := x/w.SizeX, z/w.SizeZ
+	lx, lz = x%w.SizeX, z%w.SizeZ
+	if lx < 0 {
+		lx += w.SizeX
+		cx--
+	}
+	if lz < 0 {
+		lz += w.SizeZ
+		cz--
+	}
+	return [2]int{cx, cz}, lx, lz
+}
+
+// HeightAt возвращает высоту столбца (y+1 верхнего непустого блока); ok == false, если чанк не загружен
+func (w *World) HeightAt(x, z int) (height int, ok bool) {
+	coord, lx, lz := w.locate(x, z)
+	w.Mu.RLock()
+	chunk, exists := w.Chunks[coord]
+	w.Mu.RUnlock()
+	if !exists {
+		return 0, false
+	}
+	chunk.mu.RLock()
+	defer chunk.mu.RUnlock()
+	return int(chunk.heights[lx+lz*chunk.SizeX]), true
+}
+
+// FillHeightMap заполняет out (size×size, строка — по X) высотами столбцов начиная с (x0, z0).
+// Столбцы незагруженных чанков получают missing.
+func (w *World) FillHeightMap(x0, z0, size int, missing float32, out []float32) {
+	for i := range out[:size*size] {
+		out[i] = missing
+	}
+
+	first, _, _ := w.locate(x0, z0)
+	last, _, _ := w.locate(x0+size-1, z0+size-1)
+
+	// Чанки собираем под w.Mu, а столбцы читаем уже без него
+	var chunks []*Chunk
+	w.Mu.RLock()
+	for cx := first[0]; cx <= last[0]; cx++ {
+		for cz := first[1]; cz <= last[1]; cz++ {
+			if chunk, exists := w.Chunks[[2]int{cx, cz}]; exists {
+				chunks = append(chunks, chunk)
+			}
+		}
+	}
+	w.Mu.RUnlock()
+
+	for _, chunk := range chunks {
+		cx, cz := chunk.Coord[0], chunk.Coord[1]
+		chunk.mu.RLock()
+		for lz := 0; lz < chunk.SizeZ; lz++ {
+			row := cz*chunk.SizeZ + lz - z0
+			if row < 0 || row >= size {
+				continue
+			}
+			for lx := 0; lx < chunk.SizeX; lx++ {
+				col := cx*chunk.SizeX + lx - x0
+				if col < 0 || col >= size {
+					continue
+				}
+				out[row*size+col] = float32(chunk.heights[lx+lz*chunk.SizeX])
+			}
+		}
+		chunk.mu.RUnlock()
+	}
+}
@@ -0,0 +1,206 @@
+package world
+
+import (
+	"engine/src/config"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Стресс-тест жизненного цикла чанков, рассчитан на go test -race: генераторы, правки
+// блоков на границах чанков, выгрузка и GL-поток (разбор MeshCh) работают одновременно.
+// Проверяется, что меши чанка не приходят после его выгрузки и что ChunkID на координате
+// и Version внутри чанка не идут назад.
+
+const stressGrid = 4 // чанков по каждой оси
+
+func stressConfig() *config.Config {
+	Config := config.DefaultConfig()
+	Config.MaxTerrainHeight = 0.6
+	Config.SeaLevel = 0.15
+	Config.WarpScale = 94
+	Config.WarpAmp = 60
+	return &Config
+}
+
+func TestChunkLifecycleStress(t *testing.T) {
+	w := NewWorld(16, 64, 16)
+	Config := stressConfig()
+
+	wanted := map[[2]int]bool{}
+	for x := 0; x < stressGrid; x++ {
+		for z := 0; z < stressGrid; z++ {
+			wanted[[2]int{x, z}] = true
+		}
+	}
+	ready := func(coord [2]int) bool {
+		w.Mu.RLock()
+		defer w.Mu.RUnlock()
+		_, ok := w.Chunks[coord]
+		return ok
+	}
+	center := mgl32.Vec2{stressGrid / 2, stressGrid / 2}
+
+	// GL-поток: разбирает MeshCh и проверяет порядок сообщений
+	var finishing atomic.Bool
+	consumerDone := make(chan struct{})
+	var meshes, unloads int
+	go func() {
+		defer close(consumerDone)
+		unloaded := map[uint64]bool{}
+		lastVersion := map[uint64]uint64{}
+		lastID := map[[2]int]uint64{}
+		for {
+			var mesh *ChunkMesh
+			select {
+			case mesh = <-w.MeshCh:
+			case <-time.After(300 * time.Millisecond):
+				if finishing.Load() {
+					return
+				}
+				continue
+			}
+			if mesh.ChunkID < lastID[mesh.Coord] {
+				t.Errorf("chunk %v: ChunkID went back from %d to %d", mesh.Coord, lastID[mesh.Coord], mesh.ChunkID)
+			}
+			lastID[mesh.Coord] = mesh.ChunkID
+			if unloaded[mesh.ChunkID] {
+				t.Errorf("chunk %v #%d: message after unload (unload=%v, version %d)", mesh.Coord, mesh.ChunkID, mesh.Unload, mesh.Version)
+			}
+			if mesh.Unload {
+				unloaded[mesh.ChunkID] = true
+				unloads++
+				continue
+			}
+			if mesh.Version <= lastVersion[mesh.ChunkID] {
+				t.Errorf("chunk %v #%d: version went back from %d to %d", mesh.Coord, mesh.ChunkID, lastVersion[mesh.ChunkID], mesh.Version)
+			}
+			lastVersion[mesh.ChunkID] = mesh.Version
+			mesh.MarkUploaded()
+			meshes++
+		}
+	}()
+
+	stop := make(chan struct{})
+	var producers sync.WaitGroup
+	loop := func(body func(r *rand.Rand)) {
+		producers.Add(1)
+		go func() {
+			defer producers.Done()
+			r := rand.New(rand.NewSource(rand.Int63()))
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					body(r)
+				}
+			}
+		}()
+	}
+
+	// Планировщик: как UpdateWorld, возвращает в очередь выгруженные чанки
+	loop(func(*rand.Rand) {
+		w.Scheduler.Update(wanted, ready, center, mgl32.Vec2{1, 0})
+		time.Sleep(time.Millisecond)
+	})
+
+	// Генераторы
+	var generators sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		generators.Add(1)
+		go func() {
+			defer generators.Done()
+			for {
+				coord, ok := w.Scheduler.Next()
+				if !ok {
+					return
+				}
+				w.GenerateChunk(coord[0], coord[1], Config)
+				w.Scheduler.Done(coord)
+			}
+		}()
+	}
+
+	// Правки блоков на границах чанков: перестраивают и соседей
+	for i := 0; i < 3; i++ {
+		loop(func(r *rand.Rand) {
+			// Крайний столбец чанка по одной оси, любой — по другой (чанки квадратные)
+			across := r.Intn(stressGrid)*w.SizeX + []int{0, w.SizeX - 1}[r.Intn(2)]
+			along := r.Intn(stressGrid * w.SizeZ)
+			x, z := across, along
+			if r.Intn(2) == 0 {
+				x, z = along, across
+			}
+			y := r.Intn(w.SizeY)
+			if r.Intn(2) == 0 {
+				w.SetBlock(x, y, z, Block{Id: 3, Color: [3]float32{0.5, 0.5, 0.5}})
+			} else {
+				w.RemoveBlock(x, y, z)
+			}
+			time.Sleep(100 * time.Microsecond)
+		})
+	}
+
+	// Выгрузка
+	loop(func(r *rand.Rand) {
+		w.RemoveChunk(r.Intn(stressGrid), r.Intn(stressGrid))
+		time.Sleep(2 * time.Millisecond)
+	})
+
+	time.Sleep(1500 * time.Millisecond)
+	close(stop)
+	producers.Wait()
+	w.Scheduler.Close()
+	generators.Wait()
+
+	// Правки перестраивают меши в своих горутинах: ждём, пока канал затихнет
+	finishing.Store(true)
+	<-consumerDone
+
+	if meshes == 0 || unloads == 0 {
+		t.Fatalf("stress produced %d meshes and %d unloads, want both", meshes, unloads)
+	}
+	states := w.ChunkStates()
+	if states[ChunkGenerating] != 0 || states[ChunkUnloading] != 0 {
+		t.Errorf("chunks left in transient states: %v generating, %v unloading", states[ChunkGenerating], states[ChunkUnloading])
+	}
+	t.Logf("%d meshes, %d unloads, states %v", meshes, unloads, states)
+}
+
+func TestGenerateChunkStates(t *testing.T) {
+	w := NewWorld(16, 64, 16)
+	Config := stressConfig()
+
+	w.GenerateChunk(0, 0, Config)
+	chunk := w.Chunks[[2]int{0, 0}]
+	if chunk == nil {
+		t.Fatal("chunk was not generated")
+	}
+	// Generated → Meshing → MeshReady: меш опубликован
+	mesh := <-w.MeshCh
+	if got := chunk.State(); got != ChunkMeshReady {
+		t.Errorf("state after publishing = %v, want MeshReady", got)
+	}
+	mesh.MarkUploaded()
+	if got := chunk.State(); got != ChunkUploaded {
+		t.Errorf("state after MarkUploaded = %v, want Uploaded", got)
+	}
+
+	// Координата, чей чанк ещё выгружается, не генерируется заново
+	w.Mu.Lock()
+	delete(w.Chunks, chunk.Coord)
+	w.unloading[chunk.Coord] = chunk
+	w.Mu.Unlock()
+	w.GenerateChunk(0, 0, Config)
+	if len(w.Chunks) != 0 {
+		t.Error("chunk regenerated while the previous one was unloading")
+	}
+	if states := w.ChunkStates(); states[ChunkUnloading] != 1 {
+		t.Errorf("ChunkStates()[Unloading] = %d, want 1", states[ChunkUnloading])
+	}
+}
@@ -0,0 +1,189 @@
+package world
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Уровни детализации дальних чанков: на уровне n меш строится по сетке ячеек 2ⁿ×2ⁿ×2ⁿ блоков.
+// Ячейка берёт верхний блок и высоту самого высокого столбца внутри себя, поэтому грубый меш
+// всегда накрывает более подробный. Щели на стыке чанков разных уровней закрывают «юбки» —
+// боковые грани поверхностных ячеек на краю чанка, которые рисуются, даже если сосед их скрывает.
+
+// MaxLOD — самый грубый уровень детализации (ячейки 8×8×8)
+const MaxLOD = 3
+
+// Запас в чанках вокруг границы уровня, чтобы меш не перестраивался туда-обратно
+const lodHysteresis = 1.0
+
+// Сколько верхних ячеек столбца на краю чанка получают юбку
+const lodSkirtCells = 2
+
+// lodCell — ячейка упрощённого меша
+type lodCell struct {
+	block Block   // верхний блок ячейки: его цвет и текстуры получают все грани
+	top   float32 // высота заполнения от низа ячейки в блоках, 0 — пустая
+}
+
+// setLODView запоминает положение камеры (в чанках) и границы уровней детализации
+func (w *World) setLODView(center mgl32.Vec2, distances []int) {
+	w.lodMu.Lock()
+	defer w.lodMu.Unlock()
+	w.lodCenter = center
+	w.lodDistances = distances
+}
+
+// wantedLOD — уровень детализации чанка coord при текущем положении камеры.
+// current — уровень его последнего меша, рядом с границей он сохраняется.
+func (w *World) wantedLOD(coord [2]int, current int) int {
+	w.lodMu.Lock()
+	center, distances := w.lodCenter, w.lodDistances
+	w.lodMu.Unlock()
+
+	dist := mgl32.Vec2{float32(coord[0]) + 0.5, float32(coord[1]) + 0.5}.Sub(center).Len()
+	return lodLevel(dist, distances, current)
+}
+
+// lodLevel выбирает уровень по расстоянию dist в чанках: distances[i] — начало уровня i+1
+func lodLevel(dist float32, distances []int, current int) int {
+	level := 0
+	for i, d := range distances {
+		if i >= MaxLOD {
+			break
+		}
+		if dist >= float32(d) {
+			level = i + 1
+		}
+	}
+	if level == current+1 && dist < float32(distances[current])+lodHysteresis {
+		return current
+	}
+	if level == current-1 && current-1 < len(distances) && dist > float32(distances[current-1])-lodHysteresis {
+		return current
+	}
+	return level
+}
+
+// lodStep — сторона ячейки уровня lod в блоках; уменьшается, пока не делит размеры чанка
+func (chunk *Chunk) lodStep(lod int) int {
+	step := 1 << lod
+	for step > 1 && (chunk.SizeX%step != 0 || chunk.SizeY%step != 0 || chunk.SizeZ%step != 0) {
+		step /= 2
+	}
+	return step
+}
+
+// lodCellAt сворачивает блоки ячейки (cx, cy, cz) со стороной step. Вызывающий держит chunk.mu
+func (chunk *Chunk) lodCellAt(cx, cy, cz, step int) lodCell {
+	base := cy * step
+	for y := base + step - 1; y >= base; y-- {
+		for x := cx * step; x < (cx+1)*step; x++ {
+			for z := cz * step; z < (cz+1)*step; z++ {
+				block := chunk.Blocks[blockIndex(x, y, z, chunk.SizeX, chunk.SizeY, chunk.SizeZ)]
+				if block.Id != BlockAir {
+					return lodCell{block: block, top: float32(y-base) + blockHeight(block.Id)}
+				}
+			}
+		}
+	}
+	return lodCell{}
+}
+
+// lodFaceVisible — видна ли грань ячейки c, граничащей с ячейкой n (full — сторона ячейки).
+// Ячейки заполнены не доверху, поэтому кроме типа блоков сравниваются их высоты.
+func lodFaceVisible(c, n lodCell, offsetY int, full float32) bool {
+	if n.top == 0 {
+		return true
+	}
+	if IsLiquid(n.block.Id) {
+		return !IsLiquid(c.block.Id)
+	}
+	switch offsetY {
+	case 1:
+		return c.top < full
+	case -1:
+		return n.top < full
+	}
+	return n.top < c.top
+}
+
+// GenerateLODMesh строит упрощённый меш чанка уровня lod. Точечные источники света
+// у дальних чанков не собираются. Вызывающий держит на чтение блоки чанка и его соседей.
+func (chunk *Chunk) GenerateLODMesh(lod int, neighbors map[string]*Chunk) ([]float32, []uint32) {
+	step := chunk.lodStep(lod)
+	gx, gy, gz := chunk.SizeX/step, chunk.SizeY/step, chunk.SizeZ/step
+	full := float32(step)
+
+	cells := make([]lodCell, gx*gy*gz)
+	colTop := make([]int, gx*gz) // верхняя непустая ячейка столбца
+	for cx := 0; cx < gx; cx++ {
+		for cz := 0; cz < gz; cz++ {
+			colTop[cx+cz*gx] = -1
+			for cy := 0; cy < gy; cy++ {
+				cell := chunk.lodCellAt(cx, cy, cz, step)
+				cells[cx+cy*gx+cz*gx*gy] = cell
+				if cell.top > 0 {
+					colTop[cx+cz*gx] = cy
+				}
+			}
+		}
+	}
+
+	// cellAt — ячейка по координатам сетки, за краем чанка сворачивается ячейка соседа
+	cellAt := func(cx, cy, cz int) lodCell {
+		if cy < 0 || cy >= gy {
+			return lodCell{}
+		}
+		if cx >= 0 && cx < gx && cz >= 0 && cz < gz {
+			return cells[cx+cy*gx+cz*gx*gy]
+		}
+		switch {
+		case cx < 0:
+			if neighbor := neighbors["left"]; neighbor != nil {
+				return neighbor.lodCellAt(gx-1, cy, cz, step)
+			}
+		case cx >= gx:
+			if neighbor := neighbors["right"]; neighbor != nil {
+				return neighbor.lodCellAt(0, cy, cz, step)
+			}
+		case cz < 0:
+			if neighbor := neighbors["back"]; neighbor != nil {
+				return neighbor.lodCellAt(cx, cy, gz-1, step)
+			}
+		case cz >= gz:
+			if neighbor := neighbors["front"]; neighbor != nil {
+				return neighbor.lodCellAt(cx, cy, 0, step)
+			}
+		}
+		return lodCell{} // Сосед отсутствует — считаем воздухом
+	}
+
+	var vertices []float32
+	var indices []uint32
+	for cx := 0; cx < gx; cx++ {
+		for cy := 0; cy < gy; cy++ {
+			for cz := 0; cz < gz; cz++ {
+				cell := cells[cx+cy*gx+cz*gx*gy]
+				if cell.top == 0 {
+					continue
+				}
+				origin := mgl32.Vec3{float32(cx * step), float32(cy * step), float32(cz * step)}
+				for _, face := range cubeFaces {
+					if cy == 0 && face.OffsetY == -1 {
+						continue
+					}
+					nx, nz := cx+face.OffsetX, cz+face.OffsetZ
+					visible := lodFaceVisible(cell, cellAt(nx, cy+face.OffsetY, nz), face.OffsetY, full)
+					// Юбка: сосед может быть другого уровня и ниже этой ячейки
+					border := nx < 0 || nx >= gx || nz < 0 || nz >= gz
+					if !visible && border && !IsLiquid(cell.block.Id) && cy >= colTop[cx+cz*gx]-(lodSkirtCells-1) {
+						visible = true
+					}
+					if visible {
+						vertices, indices = appendFace(vertices, indices, face, cell.block, origin, mgl32.Vec3{full, cell.top, full})
+					}
+				}
+			}
+		}
+	}
+	return vertices, indices
+}
@@ -0,0 +1,78 @@
+package world
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RaycastHit — результат трассировки луча по блокам
+type RaycastHit struct {
+	Block    [3]int     // координаты блока, в который попал луч
+	Normal   [3]int     // нормаль грани, через которую луч вошёл в блок
+	Distance float32    // расстояние от начала луча до точки входа
+	Point    mgl32.Vec3 // точка входа в блок
+}
+
+// Raycast проходит луч по сетке блоков (алгоритм Amanatides–Woo) и возвращает
+// первый непустой блок, для которого solid вернёт true. solid == nil означает «любой не-воздух».
+func (w *World) Raycast(origin, dir mgl32.Vec3, maxDist float32, solid func(Block) bool) (RaycastHit, bool) {
+	if dir.Len() == 0 {
+		return RaycastHit{}, false
+	}
+	dir = dir.Normalize()
+	if solid == nil {
+		solid = func(b Block) bool { return b.Id != 0 }
+	}
+
+	pos := [3]int{
+		int(math.Floor(float64(origin.X()))),
+		int(math.Floor(float64(origin.Y()))),
+		int(math.Floor(float64(origin.Z()))),
+	}
+	var step [3]int
+	var tMax, tDelta [3]float32
+	for i := 0; i < 3; i++ {
+		switch {
+		case dir[i] > 0:
+			step[i] = 1
+			tMax[i] = (float32(pos[i]+1) - origin[i]) / dir[i]
+			tDelta[i] = 1 / dir[i]
+		case dir[i] < 0:
+			step[i] = -1
+			tMax[i] = (float32(pos[i]) - origin[i]) / dir[i]
+			tDelta[i] = -1 / dir[i]
+		default:
+			tMax[i] = float32(math.Inf(1))
+			tDelta[i] = float32(math.Inf(1))
+		}
+	}
+
+	var normal [3]int
+	t := float32(0)
+	for t <= maxDist {
+		if solid(w.GetBlock(pos[0], pos[1], pos[2])) {
+			return RaycastHit{
+				Block:    pos,
+				Normal:   normal,
+				Distance: t,
+				Point:    origin.Add(dir.Mul(t)),
+			}, true
+		}
+
+		// Переходим в соседний блок по оси с ближайшей границей
+		axis := 0
+		if tMax[1] < tMax[axis] {
+			axis = 1
+		}
+		if tMax[2] < tMax[axis] {
+			axis = 2
+		}
+		t = tMax[axis]
+		tMax[axis] += tDelta[axis]
+		pos[axis] += step[axis]
+		normal = [3]int{}
+		normal[axis] = -step[axis]
+	}
+	return RaycastHit{}, false
+}
@@ -0,0 +1,165 @@
+package world
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Насколько сильно направление взгляда поднимает приоритет чанка (0 — не влияет)
+const viewDirWeight = 0.35
+
+// Чанки ближе этого расстояния (в чанках) грузятся первыми независимо от взгляда
+const nearChunkDist = 2.0
+
+// chunkRequest — запрос на генерацию чанка в очереди планировщика
+type chunkRequest struct {
+	coord    [2]int
+	priority float64 // меньше — важнее
+	index    int     // позиция в куче
+}
+
+// chunkQueue — приоритетная очередь запросов (container/heap)
+type chunkQueue []*chunkRequest
+
+func (q chunkQueue) Len() int           { return len(q) }
+func (q chunkQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q chunkQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *chunkQueue) Push(x any) {
+	req := x.(*chunkRequest)
+	req.index = len(*q)
+	*q = append(*q, req)
+}
+func (q *chunkQueue) Pop() any {
+	old := *q
+	n := len(old)
+	req := old[n-1]
+	old[n-1] = nil
+	req.index = -1
+	*q = old[:n-1]
+	return req
+}
+
+// ChunkScheduler раздаёт воркерам координаты чанков для генерации или перестройки
+// меша под новый уровень детализации: ближайшие
+// и находящиеся перед камерой — первыми. Каждая координата стоит в очереди
+// или генерируется не более одного раза; запросы на чанки, ушедшие из радиуса
+// до начала генерации, отменяются.
+type ChunkScheduler struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	queue    chunkQueue
+	pending  map[[2]int]*chunkRequest
+	inFlight map[[2]int]bool
+	closed   bool
+
+	cancelled int // счётчик для отладочной панели
+}
+
+func NewChunkScheduler() *ChunkScheduler {
+	s := &ChunkScheduler{
+		pending:  make(map[[2]int]*chunkRequest),
+		inFlight: make(map[[2]int]bool),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Update ставит в очередь чанки из wanted, для которых ready ложно, отменяет ожидающие запросы
+// вне wanted и пересчитывает приоритеты относительно камеры.
+// center — позиция камеры в координатах чанков, viewDir — направление взгляда в XZ.
+func (s *ChunkScheduler) Update(wanted map[[2]int]bool, ready func([2]int) bool, center mgl32.Vec2, viewDir mgl32.Vec2) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Отмена: чанк покинул радиус, а воркер его ещё не взял
+	for coord, req := range s.pending {
+		if !wanted[coord] {
+			heap.Remove(&s.queue, req.index)
+			delete(s.pending, coord)
+			s.cancelled++
+		}
+	}
+
+	for coord := range wanted {
+		if s.inFlight[coord] || ready(coord) {
+			continue
+		}
+		if req, ok := s.pending[coord]; ok {
+			req.priority = chunkPriority(coord, center, viewDir)
+			continue
+		}
+		req := &chunkRequest{coord: coord, priority: chunkPriority(coord, center, viewDir)}
+		heap.Push(&s.queue, req)
+		s.pending[coord] = req
+	}
+	heap.Init(&s.queue)
+
+	if len(s.queue) > 0 {
+		s.cond.Broadcast()
+	}
+}
+
+// Next блокируется до появления запроса и возвращает самый приоритетный.
+// Координата помечается как генерируемая до вызова Done. ok == false после Close.
+func (s *ChunkScheduler) Next() (coord [2]int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.queue) == 0 && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed {
+		return [2]int{}, false
+	}
+	req := heap.Pop(&s.queue).(*chunkRequest)
+	delete(s.pending, req.coord)
+	s.inFlight[req.coord] = true
+	return req.coord, true
+}
+
+// Done снимает с координаты отметку «генерируется»
+func (s *ChunkScheduler) Done(coord [2]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, coord)
+}
+
+// Close будит и останавливает все ожидающие воркеры
+func (s *ChunkScheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// Stats возвращает число ожидающих, генерируемых и отменённых чанков
+func (s *ChunkScheduler) Stats() (pending, inFlight, cancelled int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending), len(s.inFlight), s.cancelled
+}
+
+// chunkPriority — расстояние до камеры, уменьшенное для чанков перед камерой
+func chunkPriority(coord [2]int, center mgl32.Vec2, viewDir mgl32.Vec2) float64 {
+	to := mgl32.Vec2{float32(coord[0]) + 0.5, float32(coord[1]) + 0.5}.Sub(center)
+	dist := float64(to.Len())
+	if dist < nearChunkDist || viewDir.Len() == 0 {
+		return dist
+	}
+	facing := float64(to.Normalize().Dot(viewDir.Normalize()))
+	return dist * (1 - viewDirWeight*facing)
+}
+
+// chunkCoordOf возвращает координату чанка, содержащего мировую точку (с учётом отрицательных)
+func chunkCoordOf(x, z float32, sizeX, sizeZ int) [2]int {
+	return [2]int{
+		int(math.Floor(float64(x) / float64(sizeX))),
+		int(math.Floor(float64(z) / float64(sizeZ))),
+	}
+}
@@ -0,0 +1,126 @@
+package world
+
+// Граф видимости для отсечения пещер: чанк делится по высоте на секции SectionHeight блоков,
+// и для каждой секции при мешинге запоминается, какие её грани соединены друг с другом
+// через несплошные блоки (заливкой). Рендер обходит секции от камеры и рисует только
+// чанки, до секций которых можно дойти через такие соединения.
+
+// SectionHeight — высота секции графа видимости в блоках
+const SectionHeight = 16
+
+// Грани секции
+const (
+	FaceNegX = iota
+	FacePosX
+	FaceNegY
+	FacePosY
+	FaceNegZ
+	FacePosZ
+)
+
+// FaceOffsets — смещение к соседней секции через грань; противоположная грань — f^1
+var FaceOffsets = [6][3]int{{-1, 0, 0}, {1, 0, 0}, {0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}}
+
+// SectionVisibility — набор пар граней секции, между которыми есть проход (бит на пару)
+type SectionVisibility uint16
+
+// facePairBit — бит пары граней a и b (порядок не важен)
+func facePairBit(a, b int) SectionVisibility {
+	if a > b {
+		a, b = b, a
+	}
+	// Пары (0,1)…(4,5) нумеруются подряд: 15 бит на 6 граней
+	return 1 << (a*(11-a)/2 + b - a - 1)
+}
+
+// Connected — можно ли пройти сквозь секцию от грани a к грани b
+func (v SectionVisibility) Connected(a, b int) bool {
+	return a == b || v&facePairBit(a, b) != 0
+}
+
+// SectionCount — число секций чанка по высоте
+func (chunk *Chunk) SectionCount() int {
+	return (chunk.SizeY + SectionHeight - 1) / SectionHeight
+}
+
+// computeVisibility заливает несплошные блоки каждой секции и собирает соединённые пары граней.
+// Вызывающий держит chunk.mu на чтение.
+func (chunk *Chunk) computeVisibility() []SectionVisibility {
+	sections := make([]SectionVisibility, chunk.SectionCount())
+	visited := make([]bool, chunk.SizeX*SectionHeight*chunk.SizeZ)
+	var stack [][3]int
+
+	for s := range sections {
+		y0 := s * SectionHeight
+		height := min(SectionHeight, chunk.SizeY-y0)
+		clear(visited)
+		local := func(x, y, z int) int { return x + (y-y0)*chunk.SizeX + z*chunk.SizeX*SectionHeight }
+
+		for x := 0; x < chunk.SizeX; x++ {
+			for y := y0; y < y0+height; y++ {
+				for z := 0; z < chunk.SizeZ; z++ {
+					if visited[local(x, y, z)] || chunk.opaqueAt(x, y, z) {
+						continue
+					}
+					// Заливка одной полости: запоминаем, каких граней секции она касается
+					var faces uint8
+					visited[local(x, y, z)] = true
+					stack = append(stack[:0], [3]int{x, y, z})
+					for len(stack) > 0 {
+						p := stack[len(stack)-1]
+						stack = stack[:len(stack)-1]
+						faces |= sectionFacesAt(p, chunk.SizeX, y0, y0+height, chunk.SizeZ)
+						for _, off := range FaceOffsets {
+							n := [3]int{p[0] + off[0], p[1] + off[1], p[2] + off[2]}
+							if n[0] < 0 || n[0] >= chunk.SizeX || n[1] < y0 || n[1] >= y0+height || n[2] < 0 || n[2] >= chunk.SizeZ {
+								continue
+							}
+							if visited[local(n[0], n[1], n[2])] || chunk.opaqueAt(n[0], n[1], n[2]) {
+								continue
+							}
+							visited[local(n[0], n[1], n[2])] = true
+							stack = append(stack, n)
+						}
+					}
+					for a := 0; a < 6; a++ {
+						for b := a + 1; b < 6; b++ {
+							if faces&(1<<a) != 0 && faces&(1<<b) != 0 {
+								sections[s] |= facePairBit(a, b)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return sections
+}
+
+// sectionFacesAt — грани секции [0,sizeX)×[y0,y1)×[0,sizeZ), которых касается блок p
+func sectionFacesAt(p [3]int, sizeX, y0, y1, sizeZ int) uint8 {
+	var faces uint8
+	if p[0] == 0 {
+		faces |= 1 << FaceNegX
+	}
+	if p[0] == sizeX-1 {
+		faces |= 1 << FacePosX
+	}
+	if p[1] == y0 {
+		faces |= 1 << FaceNegY
+	}
+	if p[1] == y1-1 {
+		faces |= 1 << FacePosY
+	}
+	if p[2] == 0 {
+		faces |= 1 << FaceNegZ
+	}
+	if p[2] == sizeZ-1 {
+		faces |= 1 << FacePosZ
+	}
+	return faces
+}
+
+// opaqueAt — закрывает ли блок обзор (вода и слой снега его пропускают)
+func (chunk *Chunk) opaqueAt(x, y, z int) bool {
+	return IsSolid(chunk.Blocks[blockIndex(x, y, z, chunk.SizeX, chunk.SizeY, chunk.SizeZ)].Id)
+}
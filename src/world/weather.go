@@ -0,0 +1,342 @@
+package world
+
+import (
+	"engine/src/config"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// WeatherKind — что сейчас выпадает
+type WeatherKind int
+
+const (
+	WeatherClear WeatherKind = iota
+	WeatherRain
+	WeatherThunderstorm
+	WeatherSnow
+)
+
+func (k WeatherKind) String() string {
+	switch k {
+	case WeatherRain:
+		return "rain"
+	case WeatherThunderstorm:
+		return "thunderstorm"
+	case WeatherSnow:
+		return "snow"
+	}
+	return "clear"
+}
+
+// ParseWeatherKind разбирает имя погоды для команд консоли
+func ParseWeatherKind(s string) (WeatherKind, error) {
+	for k := WeatherClear; k <= WeatherSnow; k++ {
+		if k.String() == s {
+			return k, nil
+		}
+	}
+	if s == "thunder" || s == "storm" {
+		return WeatherThunderstorm, nil
+	}
+	return WeatherClear, fmt.Errorf("unknown weather %q", s)
+}
+
+// Climate — климат в точке мира, из биома
+type Climate struct {
+	Biome       string
+	Temperature float64 // 0 — мороз, 1 — жара
+	Humidity    float64 // 0 — сухо, 1 — влажно
+}
+
+// Snowy — в этом климате осадки выпадают снегом
+func (c Climate) Snowy() bool { return c.Temperature < 0.2 }
+
+// Arid — в этом климате осадков не бывает
+func (c Climate) Arid() bool { return c.Humidity < 0.15 }
+
+// ClimateAt возвращает климат в точке мира по тем же шумам, что и генерация биомов
+func ClimateAt(x, z float64, Config *config.Config) Climate {
+	b := biomeAt(x, z, biomeNoise, warpNoise, Config.WarpScale, Config.WarpAmp)
+	return Climate{Biome: b.Name, Temperature: b.Temperature, Humidity: b.Humidity}
+}
+
+// Атмосферный фронт — состояние автомата погоды. Вид осадков (дождь, снег) фронт
+// не задаёт: он берётся из климата там, где стоит игрок.
+type weatherFront int
+
+const (
+	frontClear weatherFront = iota
+	frontPrecipitation
+	frontStorm
+)
+
+// Длительности фронтов в секундах: [мин, макс)
+var frontDurations = map[weatherFront][2]float64{
+	frontClear:         {300, 900},
+	frontPrecipitation: {120, 400},
+	frontStorm:         {60, 200},
+}
+
+// Параметры погоды
+const (
+	weatherFadeTime  = 20.0  // секунд на нарастание или стихание осадков
+	wetTime          = 30.0  // секунд сильного дождя до полного намокания
+	dryTime          = 120.0 // секунд до полного высыхания
+	lightningMinWait = 3.0   // секунд между молниями
+	lightningMaxWait = 15.0
+	flashDecay       = 6.0 // скорость затухания вспышки, 1/с
+)
+
+// Weather — погода у игрока: автомат фронтов, плавная интенсивность осадков,
+// облачность, намокание поверхностей и вспышки молний
+type Weather struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	front     weatherFront
+	timer     float64     // секунд до смены фронта
+	forced    bool        // погода задана командой: вид осадков не зависит от климата
+	forceKind WeatherKind // вид погоды, заданный командой
+	kind      WeatherKind // вид осадков, пока интенсивность не спадёт до нуля
+	climate   Climate
+
+	intensity  float64
+	cloudCover float64
+	wetness    float64
+	flash      float64
+	nextStrike float64
+}
+
+// WeatherState — снимок погоды на кадр
+type WeatherState struct {
+	Kind       WeatherKind
+	Intensity  float32 // 0..1, сила осадков
+	CloudCover float32 // 0..1
+	Wetness    float32 // 0..1, мокрые поверхности
+	Flash      float32 // 0..1, вспышка молнии
+	Climate    Climate
+	Forced     bool
+}
+
+// NewWeather создаёт погоду, начинающуюся с ясного неба
+func NewWeather(seed int64) *Weather {
+	w := &Weather{rng: rand.New(rand.NewSource(seed)), cloudCover: frontCloudCover(frontClear)}
+	w.timer = w.randomDuration(frontClear)
+	return w
+}
+
+func (w *Weather) randomDuration(f weatherFront) float64 {
+	d := frontDurations[f]
+	return d[0] + w.rng.Float64()*(d[1]-d[0])
+}
+
+// nextFront выбирает следующий фронт; во влажном климате осадки чаще
+func (w *Weather) nextFront() weatherFront {
+	r := w.rng.Float64()
+	switch w.front {
+	case frontClear:
+		if r < 0.3+0.6*w.climate.Humidity {
+			if w.rng.Float64() < 0.2 {
+				return frontStorm
+			}
+			return frontPrecipitation
+		}
+		return frontClear
+	case frontPrecipitation:
+		if r < 0.3 {
+			return frontStorm
+		}
+		return frontClear
+	default:
+		return frontPrecipitation
+	}
+}
+
+func frontCloudCover(f weatherFront) float64 {
+	switch f {
+	case frontPrecipitation:
+		return 0.8
+	case frontStorm:
+		return 1.0
+	}
+	return 0.3
+}
+
+func frontIntensity(f weatherFront) float64 {
+	switch f {
+	case frontPrecipitation:
+		return 0.6
+	case frontStorm:
+		return 1.0
+	}
+	return 0
+}
+
+// targetKind — вид осадков для текущего фронта и климата
+func (w *Weather) targetKind() WeatherKind {
+	if w.forced {
+		return w.forceKind
+	}
+	switch {
+	case w.front == frontClear || w.climate.Arid():
+		return WeatherClear
+	case w.climate.Snowy():
+		return WeatherSnow
+	case w.front == frontStorm:
+		return WeatherThunderstorm
+	}
+	return WeatherRain
+}
+
+// Update продвигает погоду на deltaTime секунд при климате у игрока
+func (w *Weather) Update(deltaTime float64, climate Climate) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.climate = climate
+
+	w.timer -= deltaTime
+	if w.timer <= 0 {
+		w.forced = false
+		w.front = w.nextFront()
+		w.timer = w.randomDuration(w.front)
+	}
+
+	// Новый вид осадков сменяет старый, только когда старый стих
+	target := w.targetKind()
+	targetIntensity := frontIntensity(w.front)
+	if target == WeatherClear {
+		targetIntensity = 0
+	}
+	if target != w.kind && w.intensity > 0 {
+		targetIntensity = 0
+	}
+	if w.intensity == 0 {
+		w.kind = target
+	}
+	w.intensity = approach(w.intensity, targetIntensity, deltaTime/weatherFadeTime)
+
+	cover := frontCloudCover(w.front)
+	if target == WeatherClear && w.front != frontClear {
+		cover = 0.5 // в засушливом климате фронт приносит только облака
+	}
+	if w.forced && w.forceKind == WeatherClear {
+		cover = frontCloudCover(frontClear)
+	}
+	w.cloudCover = approach(w.cloudCover, cover, deltaTime/weatherFadeTime)
+
+	// Поверхности мокнут под дождём и сохнут без него
+	if w.kind == WeatherRain || w.kind == WeatherThunderstorm {
+		w.wetness = approach(w.wetness, w.intensity, deltaTime/wetTime)
+	} else {
+		w.wetness = approach(w.wetness, 0, deltaTime/dryTime)
+	}
+
+	// Молнии — только в разгар грозы
+	w.flash *= math.Exp(-flashDecay * deltaTime)
+	if w.kind == WeatherThunderstorm && w.intensity > 0.5 {
+		w.nextStrike -= deltaTime
+		if w.nextStrike <= 0 {
+			w.flash = 1
+			w.nextStrike = lightningMinWait + w.rng.Float64()*(lightningMaxWait-lightningMinWait)
+		}
+	}
+}
+
+// Set задаёт погоду на seconds секунд, после чего автомат продолжает сам
+func (w *Weather) Set(kind WeatherKind, seconds float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.forced = true
+	w.forceKind = kind
+	w.timer = seconds
+	switch kind {
+	case WeatherClear:
+		w.front = frontClear
+	case WeatherThunderstorm:
+		w.front = frontStorm
+	default:
+		w.front = frontPrecipitation
+	}
+}
+
+// Now возвращает снимок погоды
+func (w *Weather) Now() WeatherState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return WeatherState{
+		Kind:       w.kind,
+		Intensity:  float32(w.intensity),
+		CloudCover: float32(w.cloudCover),
+		Wetness:    float32(w.wetness),
+		Flash:      float32(w.flash),
+		Climate:    w.climate,
+		Forced:     w.forced,
+	}
+}
+
+func (s WeatherState) String() string {
+	forced := ""
+	if s.Forced {
+		forced = " (forced)"
+	}
+	return fmt.Sprintf("%s %.2f, clouds %.2f, wet %.2f, %s%s", s.Kind, s.Intensity, s.CloudCover, s.Wetness, s.Climate.Biome, forced)
+}
+
+// approach сдвигает value к target не больше чем на step
+func approach(value, target, step float64) float64 {
+	if value < target {
+		return math.Min(value+step, target)
+	}
+	return math.Max(value-step, target)
+}
+
+// UpdateWeather продвигает погоду по климату у игрока и в снегопад
+// наметает слои снега на открытые блоки вокруг него
+func (w *World) UpdateWeather(deltaTime float64, playerPos mgl32.Vec3, Config *config.Config) {
+	x, z := float64(playerPos.X()), float64(playerPos.Z())
+	w.Weather.Update(deltaTime, ClimateAt(x, z, Config))
+
+	state := w.Weather.Now()
+	if state.Kind != WeatherSnow || state.Intensity == 0 {
+		return
+	}
+	w.snowBudget += deltaTime * Config.SnowAccumulationRate * float64(state.Intensity)
+	radius := float64(Config.PrecipitationRadius)
+	// Слои за кадр часто ложатся в один чанк: каждый затронутый чанк перестраивается один раз
+	dirty := map[[2]int]bool{}
+	for ; w.snowBudget >= 1; w.snowBudget-- {
+		bx := int(math.Floor(x + (rand.Float64()*2-1)*radius))
+		bz := int(math.Floor(z + (rand.Float64()*2-1)*radius))
+		w.placeSnowLayer(bx, bz, dirty, Config)
+	}
+	if len(dirty) == 0 {
+		return
+	}
+	go func() {
+		for coord := range dirty {
+			w.remesh(coord)
+		}
+	}()
+}
+
+// placeSnowLayer кладёт слой снега на верхний блок столбца, если он твёрдый и открыт небу,
+// а столбец лежит в снежном климате. Чанки, которые надо перестроить, добавляются в dirty.
+func (w *World) placeSnowLayer(x, z int, dirty map[[2]int]bool, Config *config.Config) {
+	height, ok := w.HeightAt(x, z)
+	if !ok || height == 0 || height >= w.SizeY {
+		return
+	}
+	if !IsSolid(w.GetBlock(x, height-1, z).Id) {
+		return // вода, уже лежащий снег
+	}
+	if !ClimateAt(float64(x), float64(z), Config).Snowy() {
+		return
+	}
+	for _, coord := range w.setBlock(x, height, z, Block{Id: BlockSnowLayer, Color: SnowLayerColor}) {
+		dirty[coord] = true
+	}
+}
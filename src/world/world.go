@@ -4,7 +4,9 @@ import (
 	"engine/src/config"
 	"math"
 	"math/rand"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/ojrac/opensimplex-go"
@@ -16,35 +18,64 @@ type Block struct {
 	Color [3]float32
 }
 
-// Структура чанка
-type Chunk struct {
-	Blocks              []Block // Одномерный массив блоков
-	VAO                 uint32
-	VBO                 uint32
-	EBO                 uint32
-	IndicesCount        int
-	SizeX, SizeY, SizeZ int
-	Vertices            []float32
-	Indices             []uint32
-	UpdateBuf           bool
-	CreateBuf           bool
-}
-
 // Структура мира
 type World struct {
 	Mu                  sync.RWMutex
 	Chunks              map[[2]int]*Chunk
 	SizeX, SizeY, SizeZ int
+	Scheduler           *ChunkScheduler // очередь генерации чанков
+	MeshCh              chan *ChunkMesh // готовые меши и запросы на выгрузку для GL-потока
+	Clock               *Clock          // мировое время: тики, сутки, остановка
+	Weather             *Weather        // погода у игрока
+
+	nextChunkID atomic.Uint64
+	generating  map[[2]int]*Chunk // чанки, чьи блоки ещё генерируются (защищена Mu)
+	unloading   map[[2]int]*Chunk // удалённые чанки, чьё сообщение о выгрузке ещё не отправлено (защищена Mu)
+	snowBudget  float64           // накопленное число слоёв снега к укладке (только главный поток)
+
+	lodMu        sync.Mutex
+	lodCenter    mgl32.Vec2 // камера в координатах чанков на последнем UpdateChunks
+	lodDistances []int      // расстояния в чанках, с которых начинаются уровни детализации
 }
 
 // Создает новый пустой мир
 func NewWorld(sizeX, sizeY, sizeZ int) *World {
 	return &World{
-		Chunks: make(map[[2]int]*Chunk),
-		SizeX:  sizeX,
-		SizeY:  sizeY,
-		SizeZ:  sizeZ,
+		Chunks:     make(map[[2]int]*Chunk),
+		generating: make(map[[2]int]*Chunk),
+		unloading:  make(map[[2]int]*Chunk),
+		SizeX:      sizeX,
+		SizeY:      sizeY,
+		SizeZ:      sizeZ,
+		Scheduler:  NewChunkScheduler(),
+		MeshCh:     make(chan *ChunkMesh, meshQueueSize),
+		Clock:      NewClock(DefaultDayLength),
+		Weather:    NewWeather(rand.Int63()),
+	}
+}
+
+// Ёмкость канала мешей: генераторы блокируются, если GL-поток не успевает их забирать
+const meshQueueSize = 256
+
+// ChunkCount возвращает число загруженных чанков
+func (w *World) ChunkCount() int {
+	w.Mu.RLock()
+	defer w.Mu.RUnlock()
+	return len(w.Chunks)
+}
+
+// ChunkStates возвращает число чанков на каждой стадии жизненного цикла,
+// включая ещё генерируемые
+func (w *World) ChunkStates() [ChunkUnloading + 1]int {
+	var counts [ChunkUnloading + 1]int
+	w.Mu.RLock()
+	defer w.Mu.RUnlock()
+	for _, chunk := range w.Chunks {
+		counts[chunk.State()]++
 	}
+	counts[ChunkGenerating] += len(w.generating)
+	counts[ChunkUnloading] += len(w.unloading)
+	return counts
 }
 
 // Функция для вычисления индекса в одномерном массиве по (x, y, z)
@@ -125,31 +156,11 @@ func blockIndex(x, y, z, sizeX, sizeY, sizeZ int) int {
 // 	}
 // }
 
-// Генерирует буферы для чанка
-func (chunk *Chunk) CreateBuffers(neighbors map[string]*Chunk) {
-	Vertices, indices := chunk.GenerateMesh(neighbors)
-
-	chunk.IndicesCount = len(indices)
-	chunk.Vertices = Vertices
-	chunk.Indices = indices
-	chunk.CreateBuf = true
-
-}
-
-// Обновляет буферы и меш чанка
-func (chunk *Chunk) UpdateBuffers(neighbors map[string]*Chunk) {
-	Vertices, indices := chunk.GenerateMesh(neighbors)
-
-	chunk.IndicesCount = len(indices)
-	chunk.Vertices = Vertices
-	chunk.Indices = indices
-	chunk.UpdateBuf = true
-}
-
-// Генерирует меш чанка
-func (chunk *Chunk) GenerateMesh(neighbors map[string]*Chunk) ([]float32, []uint32) {
-	var vertices []float32 // здесь будем класть по 9 float на вершину
+// Генерирует меш чанка. Вызывающий держит на чтение блоки чанка и его соседей
+func (chunk *Chunk) GenerateMesh(neighbors map[string]*Chunk) ([]float32, []uint32, []PointLight) {
+	var vertices []float32 // здесь будем класть по VertexFloats float на вершину
 	var indices []uint32
+	var lights []PointLight // светящиеся блоки, у которых видна хотя бы одна грань
 
 	for x := 0; x < chunk.SizeX; x++ {
 		for y := 0; y < chunk.SizeY; y++ {
@@ -164,51 +175,34 @@ func (chunk *Chunk) GenerateMesh(neighbors map[string]*Chunk) ([]float32, []uint
 				// }
 
 				// Для каждой из 6 граней куба
+				facesBefore := len(indices)
 				for _, face := range cubeFaces {
 					if y == 0 && face.OffsetY == -1 {
 						continue
 					}
 					nx, ny, nz := x+face.OffsetX, y+face.OffsetY, z+face.OffsetZ
-					if IsAirWithNeighbors(chunk, nx, ny, nz, neighbors) {
-
-						// Нормаль этой грани:
-						// face.OffsetX, face.OffsetY, face.OffsetZ
-						// (для куба это 0,0,1 или -1,0,0 и т.д.)
-						normX := float32(face.OffsetX)
-						normY := float32(face.OffsetY)
-						normZ := float32(face.OffsetZ)
-
-						// Для удобства
-						r := block.Color[0]
-						g := block.Color[1]
-						b := block.Color[2]
-
-						startIdx := uint32(len(vertices) / 9)
-						// т.к. теперь 9 float на вершину
-
-						// Добавляем 4 вершины (квадрат)
-						for _, vtx := range face.Vertices {
-							px := float32(x) + vtx[0]
-							py := float32(y) + vtx[1]
-							pz := float32(z) + vtx[2]
-
-							vertices = append(vertices,
-								px, py, pz, // позиция
-								normX, normY, normZ, // нормаль
-								r, g, b) // цвет
-						}
-
-						// Индексы
-						indices = append(indices,
-							startIdx+0, startIdx+1, startIdx+2,
-							startIdx+2, startIdx+3, startIdx+0)
+					if faceVisible(block.Id, neighborBlockID(chunk, nx, ny, nz, neighbors), face.OffsetY) {
+						vertices, indices = appendFace(vertices, indices, face, block,
+							mgl32.Vec3{float32(x), float32(y), float32(z)}, mgl32.Vec3{1, blockHeight(block.Id), 1})
 					}
 				}
+
+				if light, ok := BlockEmission(block.Id); ok && len(indices) > facesBefore {
+					lights = append(lights, PointLight{
+						Position: mgl32.Vec3{
+							float32(chunk.Coord[0]*chunk.SizeX+x) + 0.5,
+							float32(y) + 0.5,
+							float32(chunk.Coord[1]*chunk.SizeZ+z) + 0.5,
+						},
+						Color:  light.Color,
+						Radius: light.Radius,
+					})
+				}
 			}
 		}
 	}
 
-	return vertices, indices
+	return vertices, indices, lights
 }
 
 var (
@@ -224,12 +218,16 @@ type Biome struct {
 	MaxHeightFactor float64
 	SurfaceBlock    Block
 	SoilBlock       Block
+	Temperature     float64 // климат для погоды: 0 — мороз, 1 — жара
+	Humidity        float64 // 0 — сухо (без осадков), 1 — влажно
 }
 
 // Определяем 4 «чистых» биома
 var (
 	biomeDesert = Biome{
 		Name:            "desert",
+		Temperature:     0.9,
+		Humidity:        0.05,
 		MinHeightFactor: 0.4,
 		MaxHeightFactor: 0.6,
 		SurfaceBlock: Block{
@@ -243,6 +241,8 @@ var (
 	}
 	biomePlains = Biome{
 		Name:            "plains",
+		Temperature:     0.6,
+		Humidity:        0.5,
 		MinHeightFactor: 0.55,
 		MaxHeightFactor: 0.65,
 		SurfaceBlock: Block{
@@ -256,6 +256,8 @@ var (
 	}
 	biomeForest = Biome{
 		Name:            "forest",
+		Temperature:     0.5,
+		Humidity:        0.7,
 		MinHeightFactor: 0.55,
 		MaxHeightFactor: 0.75,
 		SurfaceBlock: Block{
@@ -269,6 +271,8 @@ var (
 	}
 	biomeMountains = Biome{
 		Name:            "mountains",
+		Temperature:     0.3,
+		Humidity:        0.5,
 		MinHeightFactor: 0.7,
 		MaxHeightFactor: 2.3, // Высокие горы
 		SurfaceBlock: Block{
@@ -283,6 +287,8 @@ var (
 	// --- Новые биомы ---
 	biomeSwamp = Biome{
 		Name:            "swamp",
+		Temperature:     0.6,
+		Humidity:        0.9,
 		MinHeightFactor: 0.25, // Низкие болота
 		MaxHeightFactor: 0.5,
 		SurfaceBlock: Block{
@@ -296,6 +302,8 @@ var (
 	}
 	biomeSnow = Biome{
 		Name:            "snow",
+		Temperature:     0.0,
+		Humidity:        0.6,
 		MinHeightFactor: 0.6,
 		MaxHeightFactor: 1.2, // Будет чуть повышенный рельеф
 		SurfaceBlock: Block{
@@ -333,6 +341,8 @@ func blendBiomes(bA, bB Biome, t float64) Biome {
 		Name:            "mixed",
 		MinHeightFactor: lerp(bA.MinHeightFactor, bB.MinHeightFactor, t),
 		MaxHeightFactor: lerp(bA.MaxHeightFactor, bB.MaxHeightFactor, t),
+		Temperature:     lerp(bA.Temperature, bB.Temperature, t),
+		Humidity:        lerp(bA.Humidity, bB.Humidity, t),
 		// Упрощённо берём surface/soil от «доминантного» биома (если t<0.5 => bA)
 		// Можно усложнить и смешать цвета.
 		SurfaceBlock: func() Block {
@@ -413,10 +423,22 @@ func pickBiomeSmooth(bVal float64) Biome {
 	}
 }
 
-// ------------------- NewChunk с «warp» и плавными переходами -------------------
-func NewChunk(sizeX, sizeY, sizeZ int, offsetX, offsetZ int,
+// biomeAt — биом в точке мира: шум биомов, сдвинутый warp-шумом
+func biomeAt(worldX, worldZ float64, biomeNoise, warpNoise opensimplex.Noise, warpScale, warpAmp float64) Biome {
+	wVal := warpNoise.Eval2(worldX/warpScale, worldZ/warpScale)
+	warp := wVal * warpAmp
+
+	warpedX := worldX + warp
+	warpedZ := worldZ - warp
+	bVal := biomeNoise.Eval2(warpedX/300.0, warpedZ/300.0)
+
+	return pickBiomeSmooth(bVal)
+}
+
+// ------------------- generateBlocks с «warp» и плавными переходами -------------------
+func generateBlocks(sizeX, sizeY, sizeZ int, offsetX, offsetZ int,
 	biomeNoise, terrainNoise, warpNoise opensimplex.Noise, Config *config.Config,
-) *Chunk {
+) []Block {
 
 	blocks := make([]Block, sizeX*sizeY*sizeZ)
 
@@ -436,15 +458,7 @@ func NewChunk(sizeX, sizeY, sizeZ int, offsetX, offsetZ int,
 			worldX := float64(x + offsetX*sizeX)
 			worldZ := float64(z + offsetZ*sizeZ)
 
-			// Warp
-			wVal := warpNoise.Eval2(worldX/warpScale, worldZ/warpScale)
-			warp := wVal * warpAmp
-
-			warpedX := worldX + warp
-			warpedZ := worldZ - warp
-			bVal := biomeNoise.Eval2(warpedX/300.0, warpedZ/300.0)
-
-			currentBiome := pickBiomeSmooth(bVal)
+			currentBiome := biomeAt(worldX, worldZ, biomeNoise, warpNoise, warpScale, warpAmp)
 
 			var totalNoise float64
 			var ampSum float64
@@ -500,6 +514,10 @@ func NewChunk(sizeX, sizeY, sizeZ int, offsetX, offsetZ int,
 					}
 				}
 			}
+			// Редкие выходы лавы в горах
+			if currentBiome.Name == "mountains" && finalHeight >= seaLevel && rand.Float64() < 0.002 {
+				blocks[blockIndex(x, finalHeight, z, sizeX, sizeY, sizeZ)] = lavaBlock
+			}
 			if (currentBiome.Name == "plains" || currentBiome.Name == "forest") &&
 				finalHeight >= seaLevel && finalHeight < sizeY-1 {
 				if rand.Float64() < 0.02 {
@@ -509,12 +527,7 @@ func NewChunk(sizeX, sizeY, sizeZ int, offsetX, offsetZ int,
 		}
 	}
 
-	return &Chunk{
-		Blocks: blocks,
-		SizeX:  sizeX,
-		SizeY:  sizeY,
-		SizeZ:  sizeZ,
-	}
+	return blocks
 }
 
 // placeTree — простое «майнкрафтовское» дерево
@@ -563,38 +576,132 @@ func generateLeaves(blocks []Block, x, y, z, sizeX, sizeY, sizeZ int) {
 	}
 }
 
-// Модифицируем GenerateChunk для передачи глобальных координат
+// Генерирует чанк, добавляет его в мир и строит меши для него и его соседей.
+// Пока генерируются блоки, чанк лежит в w.generating в состоянии Generating и виден
+// только счётчикам ChunkStates; в w.Chunks он попадает уже Generated. Координата, чей
+// прежний чанк ещё выгружается, пропускается: планировщик вернёт её позже, и меши
+// нового чанка не обгонят сообщение о выгрузке старого.
+// Уже загруженный чанк перестраивается, если сменился нужный ему уровень детализации.
 func (w *World) GenerateChunk(cx, cz int, Config *config.Config) {
 
 	coord := [2]int{cx, cz}
+	w.Mu.RLock()
+	chunk, exists := w.Chunks[coord]
+	w.Mu.RUnlock()
+	if exists {
+		if w.wantedLOD(coord, chunk.LOD()) != chunk.LOD() {
+			w.remesh(coord)
+		}
+		return
+	}
+
+	newChunk := &Chunk{
+		Coord: coord,
+		ID:    w.nextChunkID.Add(1),
+		SizeX: w.SizeX,
+		SizeY: w.SizeY,
+		SizeZ: w.SizeZ,
+	}
+	newChunk.setState(ChunkGenerating)
 	w.Mu.Lock()
-	if _, exists := w.Chunks[coord]; exists {
+	_, loaded := w.Chunks[coord]
+	_, generating := w.generating[coord]
+	_, unloading := w.unloading[coord]
+	if loaded || generating || unloading {
 		w.Mu.Unlock()
 		return
 	}
+	w.generating[coord] = newChunk
 	w.Mu.Unlock()
-	// noise := opensimplex.New(2000)
 
-	newChunk := NewChunk(w.SizeX, w.SizeY, w.SizeZ, cx, cz, biomeNoise, terrainNoise, warpNoise, Config)
+	newChunk.Blocks = generateBlocks(w.SizeX, w.SizeY, w.SizeZ, cx, cz, biomeNoise, terrainNoise, warpNoise, Config)
+	newChunk.computeHeights()
 
-	// defer
 	w.Mu.Lock()
+	delete(w.generating, coord)
+	newChunk.setState(ChunkGenerated)
 	w.Chunks[coord] = newChunk
-
-	neighbors := w.collectNeighbors(cx, cz)
 	w.Mu.Unlock()
-	newChunk.CreateBuffers(neighbors)
-
-	// Обновляем соседей
-	for direction, neighbor := range neighbors {
-		if neighbor != nil {
-			w.Mu.Lock()
-			updatedNeighbors := w.collectNeighbors(cx+offsets[direction][0], cz+offsets[direction][1])
-			w.Mu.Unlock()
-			neighbor.UpdateBuffers(updatedNeighbors)
+
+	w.remesh(coord)
+	for _, off := range offsets {
+		w.remesh([2]int{cx + off[0], cz + off[1]})
+	}
+}
+
+// remesh перестраивает меш чанка нужного ему уровня детализации и публикует его в MeshCh.
+// Блоки чанка и соседей читаются под их RLock, взятыми в порядке координат,
+// поэтому одновременные мешинги и SetBlock не взаимоблокируются.
+func (w *World) remesh(coord [2]int) {
+	w.Mu.RLock()
+	chunk, exists := w.Chunks[coord]
+	neighbors := w.collectNeighbors(coord[0], coord[1])
+	w.Mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	version, ok := chunk.beginMeshing()
+	if !ok {
+		return
+	}
+	lod := w.wantedLOD(coord, chunk.LOD())
+	chunk.lod.Store(int32(lod))
+
+	locked := []*Chunk{chunk}
+	for _, n := range neighbors {
+		if n != nil {
+			locked = append(locked, n)
 		}
 	}
+	sort.Slice(locked, func(i, j int) bool {
+		a, b := locked[i].Coord, locked[j].Coord
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	for _, c := range locked {
+		c.mu.RLock()
+	}
+	var vertices []float32
+	var indices []uint32
+	var lights []PointLight
+	if lod > 0 {
+		vertices, indices = chunk.GenerateLODMesh(lod, neighbors)
+	} else {
+		vertices, indices, lights = chunk.GenerateMesh(neighbors)
+	}
+	sections := chunk.computeVisibility()
+	for _, c := range locked {
+		c.mu.RUnlock()
+	}
+
+	chunk.publishMesh(&ChunkMesh{
+		Coord:    coord,
+		ChunkID:  chunk.ID,
+		Version:  version,
+		Vertices: vertices,
+		Indices:  indices,
+		Lights:   lights,
+		Bounds:   chunk.GetBoundingBox(coord),
+		LOD:      lod,
+		Sections: sections,
+		chunk:    chunk,
+	}, w.MeshCh)
+}
 
+// RemeshAll перестраивает меши всех загруженных чанков в фоне (после смены описаний блоков)
+func (w *World) RemeshAll() {
+	w.Mu.RLock()
+	coords := make([][2]int, 0, len(w.Chunks))
+	for coord := range w.Chunks {
+		coords = append(coords, coord)
+	}
+	w.Mu.RUnlock()
+
+	go func() {
+		for _, coord := range coords {
+			w.remesh(coord)
+		}
+	}()
 }
 
 // Собирает соседние чанки
@@ -615,11 +722,14 @@ var offsets = map[string][2]int{
 	"front": {0, 1},
 }
 
-// Описание граней куба
-var cubeFaces = []struct {
+// cubeFace — грань куба: направление нормали и вершины единичного куба
+type cubeFace struct {
 	OffsetX, OffsetY, OffsetZ int
 	Vertices                  [4][3]float32
-}{
+}
+
+// Описание граней куба
+var cubeFaces = []cubeFace{
 	{0, 0, 1, [4][3]float32{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1}}},
 	{0, 0, -1, [4][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}},
 	{-1, 0, 0, [4][3]float32{{0, 0, 0}, {0, 0, 1}, {0, 1, 1}, {0, 1, 0}}},
@@ -628,96 +738,145 @@ var cubeFaces = []struct {
 	{0, -1, 0, [4][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 0, 1}, {0, 0, 1}}},
 }
 
+// appendFace добавляет в меш грань блока block — параллелепипеда с углом origin и размерами size
+func appendFace(vertices []float32, indices []uint32, face cubeFace, block Block, origin, size mgl32.Vec3) ([]float32, []uint32) {
+	// Нормаль этой грани: для куба это 0,0,1 или -1,0,0 и т.д.
+	normX := float32(face.OffsetX)
+	normY := float32(face.OffsetY)
+	normZ := float32(face.OffsetZ)
+
+	r, g, b := block.Color[0], block.Color[1], block.Color[2]
+	material := BlockMaterial(block.Id)
+	layer, tint := faceTexture(block.Id, face.OffsetY)
+	if !tint {
+		r, g, b = 1, 1, 1
+	}
+
+	startIdx := uint32(len(vertices) / VertexFloats)
+
+	// Добавляем 4 вершины (квадрат)
+	for _, vtx := range face.Vertices {
+		px := origin.X() + vtx[0]*size.X()
+		py := origin.Y() + vtx[1]*size.Y()
+		pz := origin.Z() + vtx[2]*size.Z()
+		u, v := faceUV(face.OffsetX, face.OffsetY, face.OffsetZ, px, py, pz)
+
+		vertices = append(vertices,
+			px, py, pz, // позиция
+			normX, normY, normZ, // нормаль
+			r, g, b, // цвет
+			material,
+			u, v, layer) // текстура
+	}
+
+	// Индексы
+	indices = append(indices,
+		startIdx+0, startIdx+1, startIdx+2,
+		startIdx+2, startIdx+3, startIdx+0)
+	return vertices, indices
+}
+
 // Проверяет, является ли блок воздухом с учетом соседей
 func IsAirWithNeighbors(chunk *Chunk, x, y, z int, neighbors map[string]*Chunk) bool {
-	if x >= 0 && x < chunk.SizeX && y >= 0 && y < chunk.SizeY && z >= 0 && z < chunk.SizeZ {
-		return chunk.Blocks[blockIndex(x, y, z, chunk.SizeX, chunk.SizeY, chunk.SizeZ)].Id == 0
+	return neighborBlockID(chunk, x, y, z, neighbors) == BlockAir
+}
+
+// neighborBlockID возвращает Id блока по локальным координатам чанка, заглядывая в соседние чанки.
+// За пределами мира и при отсутствии соседа — воздух
+func neighborBlockID(chunk *Chunk, x, y, z int, neighbors map[string]*Chunk) uint8 {
+	if y < 0 || y >= chunk.SizeY {
+		return BlockAir
+	}
+	if x >= 0 && x < chunk.SizeX && z >= 0 && z < chunk.SizeZ {
+		return chunk.Blocks[blockIndex(x, y, z, chunk.SizeX, chunk.SizeY, chunk.SizeZ)].Id
 	}
 
 	// Проверяем соседние чанки
 	switch {
 	case x < 0:
-		neighbor := neighbors["left"]
-		if neighbor != nil {
-			return neighbor.Blocks[blockIndex(neighbor.SizeX-1, y, z, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id == 0
-		} else {
-			return true
+		if neighbor := neighbors["left"]; neighbor != nil {
+			return neighbor.Blocks[blockIndex(neighbor.SizeX-1, y, z, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id
 		}
 	case x >= chunk.SizeX:
-		neighbor := neighbors["right"]
-		if neighbor != nil {
-			return neighbor.Blocks[blockIndex(0, y, z, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id == 0
-		} else {
-			return true
+		if neighbor := neighbors["right"]; neighbor != nil {
+			return neighbor.Blocks[blockIndex(0, y, z, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id
 		}
 	case z < 0:
-		neighbor := neighbors["back"]
-		if neighbor != nil {
-			return neighbor.Blocks[blockIndex(x, y, neighbor.SizeZ-1, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id == 0
-		} else {
-			return true
+		if neighbor := neighbors["back"]; neighbor != nil {
+			return neighbor.Blocks[blockIndex(x, y, neighbor.SizeZ-1, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id
 		}
 	case z >= chunk.SizeZ:
-		neighbor := neighbors["front"]
-		if neighbor != nil && x >= 0 && x < neighbor.SizeX && y >= 0 && y < neighbor.SizeY {
-			return neighbor.Blocks[blockIndex(x, y, 0, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id == 0
-		} else {
-			return true
+		if neighbor := neighbors["front"]; neighbor != nil {
+			return neighbor.Blocks[blockIndex(x, y, 0, neighbor.SizeX, neighbor.SizeY, neighbor.SizeZ)].Id
 		}
 	}
-	return true // Возвращаем `true`, если сосед отсутствует (вместо false)
+	return BlockAir // Сосед отсутствует — считаем воздухом
 }
-func (w *World) UpdateChunks(playerX, playerZ int, radius int, chunkGenCh chan [2]int, chunkDelCh chan [2]int) {
-	// Вычисляем центральные координаты чанка, в котором находится игрок
-	centerX, centerZ := playerX/w.SizeX, playerZ/w.SizeZ
-
-	// Множество чанков, которые должны быть загружены
-	newChunks := make(map[[2]int]bool)
 
-	// Вычисляем координаты чанков в пределах радиуса
-	for x := centerX - radius; x <= centerX+radius; x++ {
-		for z := centerZ - radius; z <= centerZ+radius; z++ {
-			coord := [2]int{x, z}
-			newChunks[coord] = true
-
-			// Генерируем новый чанк, если он еще не существует
-			w.Mu.Lock()
-			if _, exists := w.Chunks[coord]; !exists {
-				// w.GenerateChunk(x, z)
-				if len(chunkGenCh) < 99 {
-					chunkGenCh <- [2]int{x, z}
-				}
-				//fmt.Printf("%d, %d\n", x, z)
+// UpdateChunks определяет чанки в круговом радиусе вокруг камеры: недостающие и те,
+// чей меш не того уровня детализации (lodDistances — начала уровней в чанках), передаёт
+// планировщику, а слишком далёкие отправляет на удаление.
+func (w *World) UpdateChunks(cameraPos, viewDir mgl32.Vec3, radius int, lodDistances []int, chunkDelCh chan [2]int) {
+	// Позиция камеры в координатах чанков
+	center := mgl32.Vec2{cameraPos.X() / float32(w.SizeX), cameraPos.Z() / float32(w.SizeZ)}
+	w.setLODView(center, lodDistances)
+	centerCoord := chunkCoordOf(cameraPos.X(), cameraPos.Z(), w.SizeX, w.SizeZ)
+
+	// Множество чанков, которые должны быть загружены (круг, а не квадрат)
+	wanted := make(map[[2]int]bool)
+	for x := centerCoord[0] - radius; x <= centerCoord[0]+radius; x++ {
+		for z := centerCoord[1] - radius; z <= centerCoord[1]+radius; z++ {
+			dx, dz := x-centerCoord[0], z-centerCoord[1]
+			if dx*dx+dz*dz <= radius*radius {
+				wanted[[2]int{x, z}] = true
 			}
-			w.Mu.Unlock()
 		}
 	}
 
-	// Удаляем чанки, которые больше не находятся в радиусе
-	w.Mu.Lock()
+	w.Scheduler.Update(wanted, func(coord [2]int) bool {
+		w.Mu.RLock()
+		chunk, exists := w.Chunks[coord]
+		w.Mu.RUnlock()
+		return exists && w.wantedLOD(coord, chunk.LOD()) == chunk.LOD()
+	}, center, mgl32.Vec2{viewDir.X(), viewDir.Z()})
+
+	// Удаляем чанки за пределами радиуса (с запасом в один чанк, чтобы не было «дребезга» на границе)
+	unloadRadius := radius + 1
+	w.Mu.RLock()
+	var toRemove [][2]int
 	for coord := range w.Chunks {
-		if !newChunks[coord] {
-			// w.RemoveChunk(coord[0], coord[1])
-			chunkDelCh <- [2]int{coord[0], coord[1]}
+		dx, dz := coord[0]-centerCoord[0], coord[1]-centerCoord[1]
+		if dx*dx+dz*dz > unloadRadius*unloadRadius {
+			toRemove = append(toRemove, coord)
 		}
 	}
-	w.Mu.Unlock()
+	w.Mu.RUnlock()
+	for _, coord := range toRemove {
+		chunkDelCh <- coord
+	}
 }
 
-// Удаляет чанк и освобождает связанные ресурсы (VAO, VBO, EBO)
-func (w *World) RemoveChunk(cx, cz int, vramCh chan [3]uint32) {
+// Удаляет чанк из мира и просит GL-поток освободить его буферы (VAO, VBO, EBO)
+func (w *World) RemoveChunk(cx, cz int) {
 	coord := [2]int{cx, cz}
 	w.Mu.Lock()
-	defer w.Mu.Unlock()
 	chunk, exists := w.Chunks[coord]
 	if !exists {
+		w.Mu.Unlock()
 		return // Чанк уже удален или не существует
 	}
-
-	// Удаляем чанк из карты
-	vramCh <- [3]uint32{chunk.VAO, chunk.VBO, chunk.EBO}
 	delete(w.Chunks, coord)
+	w.unloading[coord] = chunk
+	w.Mu.Unlock()
+
+	// Отправляем вне w.Mu: GL-поток может ждать w.Mu, пока канал заполнен
+	chunk.unload(w.MeshCh)
+
+	w.Mu.Lock()
+	delete(w.unloading, coord)
+	w.Mu.Unlock()
 }
+
 func (chunk *Chunk) GetBoundingBox(coord [2]int) [2]mgl32.Vec3 {
 	// Минимальная точка чанка (нижний левый угол в мировых координатах)
 	min := mgl32.Vec3{
@@ -769,13 +928,31 @@ func (w *World) GetBlock(x, y, z int) Block {
 
 	// Индекс в одномерном массиве
 	idx := blockIndex(lx, y, lz, chunk.SizeX, chunk.SizeY, chunk.SizeZ)
+	chunk.mu.RLock()
+	defer chunk.mu.RUnlock()
 	return chunk.Blocks[idx]
 }
 
+// SetBlock ставит блок по мировым координатам и перестраивает меши затронутых чанков
 func (w *World) SetBlock(x, y, z int, block Block) {
+	dirty := w.setBlock(x, y, z, block)
+	if len(dirty) == 0 {
+		return
+	}
+	// SetBlock вызывается и из GL-потока, который сам разбирает MeshCh,
+	// поэтому меши строятся в отдельной горутине, а не блокируют вызывающего
+	go func() {
+		for _, coord := range dirty {
+			w.remesh(coord)
+		}
+	}()
+}
+
+// setBlock ставит блок, не перестраивая меши, и возвращает чанки, которые надо перестроить
+func (w *World) setBlock(x, y, z int, block Block) [][2]int {
 	// Проверяем, что координаты в допустимых пределах
 	if y < 0 || y >= w.SizeY {
-		return
+		return nil
 	}
 	// Координаты чанка
 	cx := x / w.SizeX
@@ -795,29 +972,41 @@ func (w *World) SetBlock(x, y, z int, block Block) {
 
 	chunkCoord := [2]int{cx, cz}
 
-	w.Mu.Lock()
-
+	w.Mu.RLock()
 	chunk, exists := w.Chunks[chunkCoord]
+	w.Mu.RUnlock()
 	if !exists {
-		return
+		return nil
 	}
-	w.Mu.Unlock()
+
 	idx := blockIndex(lx, y, lz, chunk.SizeX, chunk.SizeY, chunk.SizeZ)
+	chunk.mu.Lock()
 	chunk.Blocks[idx] = block
-
-	// Устанавливаем флаг обновления меша
-	w.Mu.Lock()
-	neighbors := w.collectNeighbors(cx, cz)
-	chunk.UpdateBuffers(neighbors)
-	w.Mu.Unlock()
-	for direction, neighbor := range neighbors {
-		if neighbor != nil {
-			w.Mu.Lock()
-			updatedNeighbors := w.collectNeighbors(cx+offsets[direction][0], cz+offsets[direction][1])
-			w.Mu.Unlock()
-			neighbor.UpdateBuffers(updatedNeighbors)
+	// Слой снега не висит в воздухе: без опоры снизу он исчезает
+	if !IsSolid(block.Id) && y+1 < chunk.SizeY {
+		above := blockIndex(lx, y+1, lz, chunk.SizeX, chunk.SizeY, chunk.SizeZ)
+		if chunk.Blocks[above].Id == BlockSnowLayer {
+			chunk.Blocks[above] = Block{Id: BlockAir}
 		}
 	}
+	chunk.updateColumnHeight(lx, lz)
+	chunk.mu.Unlock()
+
+	// Соседей перестраиваем, только если блок лежит на границе чанка
+	dirty := [][2]int{chunkCoord}
+	if lx == 0 {
+		dirty = append(dirty, [2]int{cx - 1, cz})
+	}
+	if lx == w.SizeX-1 {
+		dirty = append(dirty, [2]int{cx + 1, cz})
+	}
+	if lz == 0 {
+		dirty = append(dirty, [2]int{cx, cz - 1})
+	}
+	if lz == w.SizeZ-1 {
+		dirty = append(dirty, [2]int{cx, cz + 1})
+	}
+	return dirty
 }
 
 // RemoveBlock удаляет блок по мировым координатам (ставит воздух)